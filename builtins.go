@@ -1,7 +1,9 @@
 package parens
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 )
 
 var (
@@ -35,9 +37,71 @@ func (ba BuiltinAnalyzer) Analyze(env *Env, form Any) (Expr, error) {
 				Cause:   ErrNotFound,
 				Message: string(f),
 			}
+		} else if env.scope != nil {
+			// forms within a lexical scope are analyzed once and evaluated
+			// again with different bindings (e.g., fn bodies).
+			return &ResolveExpr{Env: env, Name: string(f)}, nil
 		}
 		return &ConstExpr{Const: v}, nil
 
+	case MetaSymbol:
+		return ba.Analyze(env, f.Symbol)
+
+	case *Vector:
+		var items []Expr
+		err := ForEach(f, func(item Any) (bool, error) {
+			expr, err := ba.Analyze(env, item)
+			if err != nil {
+				return true, WithPos(err, itemPos(f, len(items)))
+			}
+			items = append(items, expr)
+			return false, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &VectorExpr{Items: items, Meta: f.Meta()}, nil
+
+	case *Set:
+		var items []Expr
+		err := ForEach(f, func(item Any) (bool, error) {
+			expr, err := ba.Analyze(env, item)
+			items = append(items, expr)
+			return err != nil, err
+		})
+		if err != nil {
+			// items of a set are unordered and carry no position.
+			return nil, WithPos(err, f.Span().Begin)
+		}
+		return &SetExpr{Items: items, Meta: f.Meta(), Pos: f.Span().Begin}, nil
+
+	case *Map:
+		me := MapExpr{Meta: f.Meta(), Pos: f.Span().Begin}
+		err := f.each(func(key, val Any) error {
+			keyExpr, err := ba.Analyze(env, key)
+			if err != nil {
+				return err
+			}
+
+			valExpr, err := ba.Analyze(env, val)
+			if err != nil {
+				return err
+			}
+
+			me.Keys = append(me.Keys, keyExpr)
+			me.Vals = append(me.Vals, valExpr)
+			return nil
+		})
+		if err != nil {
+			// entries of a map are unordered and carry no position.
+			return nil, WithPos(err, f.Span().Begin)
+		}
+		return &me, nil
+
+	case String:
+		// strings are seqs of characters but evaluate to themselves.
+		return &ConstExpr{Const: f}, nil
+
 	case Seq:
 		cnt, err := f.Count()
 		if err != nil {
@@ -46,13 +110,27 @@ func (ba BuiltinAnalyzer) Analyze(env *Env, form Any) (Expr, error) {
 			break
 		}
 
-		return ba.analyzeSeq(env, f)
+		expr, err := ba.analyzeSeq(env, f)
+		if err != nil {
+			// errors from within the form that are not annotated already
+			// are reported at the position of this form.
+			return nil, WithPos(err, spanOf(f).Begin)
+		}
+		return expr, nil
 	}
 
 	return &ConstExpr{Const: form}, nil
 }
 
 func (ba BuiltinAnalyzer) analyzeSeq(env *Env, seq Seq) (Expr, error) {
+	// nested forms reach here without going through Env.Eval, so macro
+	// forms must be expanded before analysis.
+	if expanded, err := env.expander.Expand(env, seq); err != nil {
+		return nil, err
+	} else if expanded != nil {
+		return ba.Analyze(env, expanded)
+	}
+
 	//	Analyze the call target.  This is the first item in the sequence.
 	first, err := seq.First()
 	if err != nil {
@@ -68,31 +146,185 @@ func (ba BuiltinAnalyzer) analyzeSeq(env *Env, seq Seq) (Expr, error) {
 			if err != nil {
 				return nil, err
 			}
-			return parse(env, next)
+			expr, err := parse(env, next)
+			if err != nil {
+				return nil, err
+			}
+
+			// metadata on the fn form (e.g., ^{:doc "..."} (fn [] ...))
+			// belongs to the fn value.
+			if fe, ok := expr.(FnExpr); ok {
+				fe.Meta = Meta(seq)
+				return fe, nil
+			}
+			return expr, nil
 		}
 	}
 
 	// Call target is not a special form and must be a Invokable.  Analyze
 	// the arguments and create an InvokeExpr.
-	ie := InvokeExpr{Name: fmt.Sprintf("%s", first)}
-	err = ForEach(seq, func(item Any) (done bool, err error) {
-		if ie.Target == nil {
-			ie.Target, err = ba.Analyze(env, first)
-			return
+	ie := InvokeExpr{
+		Env:  env,
+		Name: formName(first),
+		Pos:  spanOf(seq).Begin,
+	}
+
+	idx := -1
+	err = ForEach(seq, func(item Any) (bool, error) {
+		idx++
+		expr, err := ba.Analyze(env, item)
+		if err != nil {
+			return true, WithPos(err, itemPos(seq, idx))
 		}
 
-		var arg Expr
-		if arg, err = ba.Analyze(env, item); err == nil {
-			ie.Args = append(ie.Args, arg)
+		if ie.Target == nil {
+			ie.Target = expr
+		} else {
+			ie.Args = append(ie.Args, expr)
 		}
-		return
+		return false, nil
 	})
-	return &ie, err
+	if err != nil {
+		return nil, err
+	}
+
+	return &ie, nil
 }
 
 type builtinExpander struct{}
 
-func (be builtinExpander) Expand(_ *Env, _ Any) (Any, error) {
-	// TODO: implement macro expansion.
-	return nil, nil
+// Expand expands the form repeatedly until the result is no longer a macro
+// form. Returns nil, nil if the form is not a macro form.
+func (be builtinExpander) Expand(env *Env, form Any) (Any, error) {
+	var expanded Any
+	for {
+		res, ok, err := macroExpand1(env, form)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			return expanded, nil
+		}
+		form, expanded = res, res
+	}
+}
+
+// macroExpand1 expands the form once if it is an invocation of a macro. ok
+// is false if the form is not a macro form.
+func macroExpand1(env *Env, form Any) (res Any, ok bool, err error) {
+	seq, isSeq := form.(Seq)
+	if !isSeq {
+		return nil, false, nil
+	}
+
+	first, err := seq.First()
+	if err != nil {
+		return nil, false, err
+	}
+
+	sym, isSym := first.(Symbol)
+	if !isSym {
+		return nil, false, nil
+	}
+
+	macro, isFn := env.Resolve(string(sym)).(*Fn)
+	if !isFn || !macro.Macro {
+		return nil, false, nil
+	}
+
+	rest, err := seq.Next()
+	if err != nil {
+		return nil, false, err
+	}
+
+	args, err := toSlice(rest)
+	if err != nil {
+		return nil, false, err
+	}
+
+	frame := StackFrame{Name: string(sym), Args: args, Pos: spanOf(form).Begin}
+	if err := env.push(frame); err != nil {
+		return nil, false, err
+	}
+	defer env.pop()
+
+	res, err = macro.Invoke(env, args...)
+	return res, err == nil, err
+}
+
+func builtinGlobals() map[string]Any {
+	return map[string]Any{
+		"macroexpand-1": GoFunc(macroExpand1Fn),
+		"macroexpand":   GoFunc(macroExpandFn),
+		"gensym":        GoFunc(gensymFn),
+		"first":         GoFunc(firstFn),
+		"rest":          GoFunc(restFn),
+		"count":         GoFunc(countFn),
+		"cons":          GoFunc(consFn),
+		"concat":        GoFunc(concatFn),
+		"map":           GoFunc(mapFn),
+		"filter":        GoFunc(filterFn),
+		"reduce":        GoFunc(reduceFn),
+		"range":         GoFunc(rangeFn),
+		"iterate":       GoFunc(iterateFn),
+		"take":          GoFunc(takeFn),
+		"drop":          GoFunc(dropFn),
+		"re-find":       GoFunc(reFindFn),
+		"re-matches":    GoFunc(reMatchesFn),
+		"re-seq":        GoFunc(reSeqFn),
+		"re-groups":     GoFunc(reGroupsFn),
+		"meta":          GoFunc(metaFn),
+		"with-meta":     GoFunc(withMetaFn),
+		"vary-meta":     GoFunc(varyMetaFn),
+	}
+}
+
+// macroExpand1Fn implements (macroexpand-1 form). Returns the form expanded
+// once if it is a macro form, the form itself otherwise.
+func macroExpand1Fn(env *Env, args ...Any) (Any, error) {
+	if err := checkArity("macroexpand-1", args, 1); err != nil {
+		return nil, err
+	}
+
+	res, ok, err := macroExpand1(env, args[0])
+	if err != nil || !ok {
+		return args[0], err
+	}
+	return res, nil
+}
+
+// macroExpandFn implements (macroexpand form). Returns the form expanded
+// repeatedly until it is no longer a macro form.
+func macroExpandFn(env *Env, args ...Any) (Any, error) {
+	if err := checkArity("macroexpand", args, 1); err != nil {
+		return nil, err
+	}
+
+	res, err := env.expander.Expand(env, args[0])
+	if err != nil || res == nil {
+		return args[0], err
+	}
+	return res, nil
+}
+
+// gensymFn implements (gensym) and (gensym prefix). Returns a new symbol with
+// a unique name. Prefix defaults to "G__".
+func gensymFn(_ *Env, args ...Any) (Any, error) {
+	prefix := String("G__")
+	if len(args) > 1 {
+		return nil, Error{
+			Cause:   ErrArity,
+			Message: fmt.Sprintf("gensym requires at most 1 argument, got %d", len(args)),
+		}
+	} else if len(args) == 1 {
+		s, ok := args[0].(String)
+		if !ok {
+			return nil, Error{
+				Cause:   errors.New("invalid gensym prefix"),
+				Message: fmt.Sprintf("prefix must be string, not '%s'", reflect.TypeOf(args[0])),
+			}
+		}
+		prefix = s
+	}
+
+	return gensym(string(prefix), ""), nil
 }
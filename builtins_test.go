@@ -2,6 +2,7 @@ package parens_test
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/spy16/parens"
@@ -57,6 +58,9 @@ func TestBasicAnalyzer_Analyze(t *testing.T) {
 			env := parens.New(parens.WithGlobals(map[string]parens.Any{
 				"str": parens.String("hello"),
 			}, nil))
+			if ie, ok := tt.want.(*parens.InvokeExpr); ok {
+				ie.Env = env
+			}
 
 			az := &parens.BuiltinAnalyzer{}
 			got, err := az.Analyze(env, tt.form)
@@ -70,3 +74,123 @@ func TestBasicAnalyzer_Analyze(t *testing.T) {
 		})
 	}
 }
+
+func TestBuiltinExpander_Expand(t *testing.T) {
+	t.Parallel()
+
+	const macros = `
+(defmacro unless (test then) (list 'if test nil then))
+(defmacro m1 (x) (list 'm2 x))
+(defmacro m2 (x) (list 'quote x))
+(defmacro when (test & body) ` + "`" + `(if ~test (do ~@body)))
+`
+
+	table := []struct {
+		title   string
+		src     string
+		want    parens.Any
+		wantErr bool
+	}{
+		{
+			title: "Macro Call",
+			src:   `(unless false :ok)`,
+			want:  parens.Keyword("ok"),
+		},
+		{
+			title: "Nested Macro Call",
+			src:   `(list (unless false :ok))`,
+			want:  parens.NewList(parens.Keyword("ok")),
+		},
+		{
+			title: "Expand Until Fixpoint",
+			src:   `(m1 hello)`,
+			want:  parens.Symbol("hello"),
+		},
+		{
+			title: "Syntax Quoted Template",
+			src:   `(when true :a :b)`,
+			want:  parens.Keyword("b"),
+		},
+		{
+			title: "MacroExpand1",
+			src:   `(macroexpand-1 '(m1 hello))`,
+			want:  parens.NewList(parens.Symbol("m2"), parens.Symbol("hello")),
+		},
+		{
+			title: "MacroExpand",
+			src:   `(macroexpand '(m1 hello))`,
+			want:  parens.NewList(parens.Symbol("quote"), parens.Symbol("hello")),
+		},
+		{
+			title: "MacroExpand Non Macro Form",
+			src:   `(macroexpand '(list 1))`,
+			want:  parens.NewList(parens.Symbol("list"), parens.Int64(1)),
+		},
+		{
+			title:   "MacroExpand Arity",
+			src:     `(macroexpand)`,
+			wantErr: true,
+		},
+		{
+			title:   "Macro Arity",
+			src:     `(unless false)`,
+			wantErr: true,
+		},
+		{
+			title:   "Defmacro Without Name",
+			src:     `(defmacro (x) x)`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			env := parens.New(parens.WithGlobals(map[string]parens.Any{
+				"list": parens.GoFunc(func(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+					return parens.NewList(args...), nil
+				}),
+			}, nil))
+
+			_, err := evalString(env, macros)
+			requireNoErr(t, err)
+
+			got, err := evalString(env, tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Eval() error = %#v, wantErr %#v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				// forms read from source carry positions, compare by value.
+				if eq, err := parens.Eq(tt.want, got); err != nil || !eq {
+					t.Errorf("got=%#v\nwant=%#v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGensym(t *testing.T) {
+	t.Parallel()
+
+	env := parens.New()
+
+	a, err := evalString(env, `(gensym)`)
+	requireNoErr(t, err)
+	b, err := evalString(env, `(gensym)`)
+	requireNoErr(t, err)
+	if a == b || !strings.HasPrefix(string(a.(parens.Symbol)), "G__") {
+		t.Errorf("expected unique symbols with G__ prefix, got %v and %v", a, b)
+	}
+
+	c, err := evalString(env, `(gensym "tmp")`)
+	requireNoErr(t, err)
+	if !strings.HasPrefix(string(c.(parens.Symbol)), "tmp") {
+		t.Errorf("expected symbol with tmp prefix, got %v", c)
+	}
+
+	_, err = evalString(env, `(gensym :tmp)`)
+	assertErr(t, err)
+
+	_, err = evalString(env, `(gensym "a" "b")`)
+	assertErr(t, err)
+}
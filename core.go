@@ -0,0 +1,448 @@
+package parens
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// firstFn implements (first coll). Returns nil if coll is empty.
+func firstFn(env *Env, args ...Any) (Any, error) {
+	if err := checkArity("first", args, 1); err != nil {
+		return nil, err
+	}
+
+	seq, err := toSeq("first", args[0])
+	if err != nil || seq == nil {
+		return Nil{}, err
+	}
+
+	v, err := firstOf(env.realizer, seq)
+	if err != nil || v == nil {
+		return Nil{}, err
+	}
+	return v, nil
+}
+
+// restFn implements (rest coll). Returns the items after the first as a seq
+// which is empty if there are none.
+func restFn(env *Env, args ...Any) (Any, error) {
+	if err := checkArity("rest", args, 1); err != nil {
+		return nil, err
+	}
+
+	seq, err := toSeq("rest", args[0])
+	if err != nil || seq == nil {
+		return NewList(), err
+	}
+
+	next, err := nextOf(env.realizer, seq)
+	if err != nil || next == nil {
+		return NewList(), err
+	}
+	return next, nil
+}
+
+// countFn implements (count coll).
+func countFn(env *Env, args ...Any) (Any, error) {
+	if err := checkArity("count", args, 1); err != nil {
+		return nil, err
+	}
+
+	seq, err := toSeq("count", args[0])
+	if err != nil || seq == nil {
+		return Int64(0), err
+	}
+
+	cnt, err := countSeq(env, seq)
+	if err != nil {
+		return nil, err
+	}
+	return Int64(cnt), nil
+}
+
+// consFn implements (cons x coll). Returns a list with x as the first item
+// and coll as the rest.
+func consFn(_ *Env, args ...Any) (Any, error) {
+	if err := checkArity("cons", args, 2); err != nil {
+		return nil, err
+	}
+
+	seq, err := toSeq("cons", args[1])
+	if err != nil {
+		return nil, err
+	}
+	return Cons(args[0], seq)
+}
+
+// concatFn implements (concat & colls). Returns a lazy seq of the items of
+// all the colls in order.
+func concatFn(_ *Env, args ...Any) (Any, error) {
+	seqs := make([]Seq, len(args))
+	for i, arg := range args {
+		seq, err := toSeq("concat", arg)
+		if err != nil {
+			return nil, err
+		}
+		seqs[i] = seq
+	}
+	return lazyConcat(seqs), nil
+}
+
+// mapFn implements (map f coll & colls). Returns a lazy seq of results of
+// invoking f with the items at the same position in each of the colls. Stops
+// when any of the colls is exhausted.
+func mapFn(env *Env, args ...Any) (Any, error) {
+	if len(args) < 2 {
+		return nil, Error{
+			Cause:   ErrArity,
+			Message: fmt.Sprintf("map requires at least 2 arguments, got %d", len(args)),
+		}
+	}
+
+	seqs := make([]Seq, len(args)-1)
+	for i, arg := range args[1:] {
+		seq, err := toSeq("map", arg)
+		if err != nil {
+			return nil, err
+		}
+		seqs[i] = seq
+	}
+	return lazyMap(env.forkSeq(), args[0], seqs), nil
+}
+
+// filterFn implements (filter pred coll). Returns a lazy seq of the items of
+// coll for which pred returns a truthy value.
+func filterFn(env *Env, args ...Any) (Any, error) {
+	if err := checkArity("filter", args, 2); err != nil {
+		return nil, err
+	}
+
+	seq, err := toSeq("filter", args[1])
+	if err != nil {
+		return nil, err
+	}
+	return lazyFilter(env.forkSeq(), args[0], seq), nil
+}
+
+// rangeFn implements (range), (range end), (range start end) and (range start
+// end step). Returns a lazy seq of integers from start (inclusive, defaults to
+// 0) to end (exclusive) by step (defaults to 1). Without end, the seq is
+// infinite.
+func rangeFn(_ *Env, args ...Any) (Any, error) {
+	if len(args) > 3 {
+		return nil, Error{
+			Cause:   ErrArity,
+			Message: fmt.Sprintf("range requires at most 3 arguments, got %d", len(args)),
+		}
+	}
+
+	nums := make([]Int64, len(args))
+	for i, arg := range args {
+		n, ok := arg.(Int64)
+		if !ok {
+			return nil, Error{
+				Cause:   errors.New("invalid range"),
+				Message: fmt.Sprintf("arguments must be integers, not '%s'", reflect.TypeOf(arg)),
+			}
+		}
+		nums[i] = n
+	}
+
+	switch len(nums) {
+	case 0:
+		return lazyRange(0, 0, 1, false), nil
+	case 1:
+		return lazyRange(0, nums[0], 1, true), nil
+	case 2:
+		return lazyRange(nums[0], nums[1], 1, true), nil
+	}
+
+	if nums[2] == 0 {
+		return nil, Error{
+			Cause:   errors.New("invalid range"),
+			Message: "step must not be zero",
+		}
+	}
+	return lazyRange(nums[0], nums[1], nums[2], true), nil
+}
+
+// iterateFn implements (iterate f x). Returns an infinite lazy seq of x,
+// (f x), (f (f x)) and so on.
+func iterateFn(env *Env, args ...Any) (Any, error) {
+	if err := checkArity("iterate", args, 2); err != nil {
+		return nil, err
+	}
+	return lazyIterate(env.forkSeq(), args[0], args[1]), nil
+}
+
+// takeFn implements (take n coll). Returns a lazy seq of the first n items
+// of coll.
+func takeFn(_ *Env, args ...Any) (Any, error) {
+	n, seq, err := countAndSeq("take", args)
+	if err != nil {
+		return nil, err
+	}
+	return lazyTake(n, seq), nil
+}
+
+// dropFn implements (drop n coll). Returns a lazy seq of the items of coll
+// after the first n.
+func dropFn(_ *Env, args ...Any) (Any, error) {
+	n, seq, err := countAndSeq("drop", args)
+	if err != nil {
+		return nil, err
+	}
+	return lazyDrop(n, seq), nil
+}
+
+// reduceFn implements (reduce f coll) and (reduce f init coll). If init is
+// not given, the first item of coll is used as the initial value and (f) is
+// returned if coll is empty.
+func reduceFn(env *Env, args ...Any) (Any, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return nil, Error{
+			Cause:   ErrArity,
+			Message: fmt.Sprintf("reduce requires 2 or 3 arguments, got %d", len(args)),
+		}
+	}
+
+	seq, err := toSeq("reduce", args[len(args)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	var acc Any
+	if len(args) == 3 {
+		acc = args[1]
+	} else {
+		if acc, err = firstOf(env.realizer, seq); err != nil {
+			return nil, err
+		}
+
+		if acc == nil {
+			return invoke(env, args[0])
+		}
+
+		if seq, err = nextOf(env.realizer, seq); err != nil {
+			return nil, err
+		}
+	}
+
+	err = forEach(env.realizer, seq, func(item Any) (bool, error) {
+		acc, err = invoke(env, args[0], acc, item)
+		return err != nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+func lazyConcat(seqs []Seq) *LazySeq {
+	infinite := false
+	for _, seq := range seqs {
+		infinite = infinite || isInfinite(seq)
+	}
+
+	return &LazySeq{infinite: infinite, fn: func(r *realizer) (Seq, error) {
+		for len(seqs) > 0 {
+			v, err := firstOf(r, seqs[0])
+			if err != nil {
+				return nil, err
+			} else if v == nil {
+				seqs = seqs[1:]
+				continue
+			}
+
+			next, err := nextOf(r, seqs[0])
+			if err != nil {
+				return nil, err
+			}
+			rest := append([]Seq{next}, seqs[1:]...)
+			return Cons(v, lazyConcat(rest))
+		}
+		return nil, nil
+	}}
+}
+
+// lazyMap, lazyFilter and lazyIterate invoke functions on env, which must be a
+// fork of the env creating the seq since the seq can be realized concurrently
+// with the creator. All items of a seq are realized one after the other on the
+// same fork, so the bodies of the functions are analyzed once per seq.
+func lazyMap(env *Env, f Any, seqs []Seq) *LazySeq {
+	infinite := true
+	for _, seq := range seqs {
+		infinite = infinite && isInfinite(seq)
+	}
+
+	return &LazySeq{infinite: infinite, fn: func(r *realizer) (Seq, error) {
+		args := make([]Any, len(seqs))
+		rest := make([]Seq, len(seqs))
+		for i, seq := range seqs {
+			v, err := firstOf(r, seq)
+			if err != nil || v == nil {
+				return nil, err
+			}
+			args[i] = v
+
+			if rest[i], err = nextOf(r, seq); err != nil {
+				return nil, err
+			}
+		}
+
+		return env.withRealizer(r, func() (Seq, error) {
+			v, err := invoke(env, f, args...)
+			if err != nil {
+				return nil, err
+			}
+			return Cons(v, lazyMap(env, f, rest))
+		})
+	}}
+}
+
+func lazyFilter(env *Env, pred Any, seq Seq) *LazySeq {
+	return &LazySeq{infinite: isInfinite(seq), fn: func(r *realizer) (Seq, error) {
+		return env.withRealizer(r, func() (Seq, error) {
+			for seq != nil {
+				v, err := firstOf(r, seq)
+				if err != nil || v == nil {
+					return nil, err
+				}
+
+				if seq, err = nextOf(r, seq); err != nil {
+					return nil, err
+				}
+
+				keep, err := invoke(env, pred, v)
+				if err != nil {
+					return nil, err
+				} else if IsTruthy(keep) {
+					return Cons(v, lazyFilter(env, pred, seq))
+				}
+			}
+			return nil, nil
+		})
+	}}
+}
+
+func lazyRange(start, end, step Int64, bounded bool) *LazySeq {
+	return &LazySeq{infinite: !bounded, fn: func(*realizer) (Seq, error) {
+		if bounded && ((step > 0 && start >= end) || (step < 0 && start <= end)) {
+			return nil, nil
+		}
+		return Cons(start, lazyRange(start+step, end, step, bounded))
+	}}
+}
+
+func lazyIterate(env *Env, f Any, x Any) *LazySeq {
+	return &LazySeq{infinite: true, fn: func(*realizer) (Seq, error) {
+		return Cons(x, &LazySeq{infinite: true, fn: func(r *realizer) (Seq, error) {
+			return env.withRealizer(r, func() (Seq, error) {
+				next, err := invoke(env, f, x)
+				if err != nil {
+					return nil, err
+				}
+				return lazyIterate(env, f, next).realize(r)
+			})
+		}})
+	}}
+}
+
+func lazyTake(n int, seq Seq) *LazySeq {
+	return &LazySeq{fn: func(r *realizer) (Seq, error) {
+		if n <= 0 {
+			return nil, nil
+		}
+
+		v, err := firstOf(r, seq)
+		if err != nil || v == nil {
+			return nil, err
+		}
+
+		next, err := nextOf(r, seq)
+		if err != nil {
+			return nil, err
+		}
+		return Cons(v, lazyTake(n-1, next))
+	}}
+}
+
+func lazyDrop(n int, seq Seq) *LazySeq {
+	return &LazySeq{infinite: isInfinite(seq), fn: func(r *realizer) (Seq, error) {
+		var err error
+		for ; n > 0 && seq != nil; n-- {
+			if seq, err = nextOf(r, seq); err != nil {
+				return nil, err
+			}
+		}
+
+		if v, err := firstOf(r, seq); err != nil || v == nil {
+			return nil, err
+		}
+		return seq, nil
+	}}
+}
+
+// countAndSeq validates and returns the arguments of (name n coll).
+func countAndSeq(name string, args []Any) (int, Seq, error) {
+	if err := checkArity(name, args, 2); err != nil {
+		return 0, nil, err
+	}
+
+	n, ok := args[0].(Int64)
+	if !ok {
+		return 0, nil, Error{
+			Cause:   fmt.Errorf("invalid %s", name),
+			Message: fmt.Sprintf("count must be integer, not '%s'", reflect.TypeOf(args[0])),
+		}
+	}
+
+	seq, err := toSeq(name, args[1])
+	return int(n), seq, err
+}
+
+// toSeq returns the value as a Seq. Nil is returned as a nil Seq. Returns
+// error with ErrNotSeq cause if the value is not a Seq.
+func toSeq(name string, v Any) (Seq, error) {
+	if IsNil(v) {
+		return nil, nil
+	}
+
+	seq, ok := v.(Seq)
+	if !ok {
+		return nil, Error{
+			Cause:   ErrNotSeq,
+			Message: fmt.Sprintf("%s: value of type '%s'", name, reflect.TypeOf(v)),
+		}
+	}
+	return seq, nil
+}
+
+// invoke invokes the value with the arguments. The invocation is recorded
+// on the call stack of the env.
+func invoke(env *Env, v Any, args ...Any) (Any, error) {
+	fn, ok := v.(Invokable)
+	if !ok {
+		return nil, Error{
+			Cause:   ErrNotInvokable,
+			Message: fmt.Sprintf("value of type '%s' is not invokable", reflect.TypeOf(v)),
+		}
+	}
+
+	if err := env.ctxErr(); err != nil {
+		return nil, err
+	}
+
+	name := "fn"
+	if f, ok := v.(*Fn); ok && f.Name != "" {
+		name = f.Name
+	}
+
+	if err := env.push(StackFrame{Name: name, Args: args}); err != nil {
+		return nil, err
+	}
+	defer env.pop()
+
+	return fn.Invoke(env, args...)
+}
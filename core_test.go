@@ -0,0 +1,138 @@
+package parens_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spy16/parens"
+)
+
+func TestCoreSeqFns(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    string
+		wantErr error
+	}{
+		{title: "First", src: `(first '(1 2))`, want: "1"},
+		{title: "FirstVector", src: `(first [:a :b])`, want: ":a"},
+		{title: "FirstEmpty", src: `(first [])`, want: "nil"},
+		{title: "FirstNil", src: `(first nil)`, want: "nil"},
+		{title: "FirstNotSeq", src: `(first 1)`, wantErr: parens.ErrNotSeq},
+		{title: "FirstArity", src: `(first)`, wantErr: parens.ErrArity},
+		{title: "FirstString", src: `(first "λx")`, want: `\λ`},
+		{title: "FirstEmptyString", src: `(first "")`, want: "nil"},
+		{title: "RestString", src: `(rest "abc")`, want: `"bc"`},
+		{title: "CountString", src: `(count "héllo")`, want: "5"},
+		{title: "MapString", src: `(map (fn [c] [c]) "ab")`, want: `([\a] [\b])`},
+		{title: "Rest", src: `(rest '(1 2 3))`, want: "(2 3)"},
+		{title: "RestVector", src: `(rest [1 2 3])`, want: "(2 3)"},
+		{title: "RestSingle", src: `(rest [1])`, want: "()"},
+		{title: "RestNil", src: `(rest nil)`, want: "()"},
+		{title: "Count", src: `(count [1 2 3])`, want: "3"},
+		{title: "CountMap", src: `(count {:a 1 :b 2})`, want: "2"},
+		{title: "CountNil", src: `(count nil)`, want: "0"},
+		{title: "Cons", src: `(cons 0 [1 2])`, want: "(0 1 2)"},
+		{title: "ConsNil", src: `(cons 0 nil)`, want: "(0)"},
+		{title: "Concat", src: `(concat '(1) [2 3] nil #{4})`, want: "(1 2 3 4)"},
+		{title: "ConcatNoArgs", src: `(concat)`, want: "()"},
+		{title: "Map", src: `(map (fn [x] [x]) '(1 2))`, want: "([1] [2])"},
+		{title: "MapMultiColls", src: `(map (fn [x y] [x y]) [1 2 3] '(:a :b))`, want: "([1 :a] [2 :b])"},
+		{title: "MapFnLiteral", src: `(map #(cons %2 %&) [1 2] [3 4])`, want: "((3) (4))"},
+		{title: "MapEmpty", src: `(map (fn [x] x) nil)`, want: "()"},
+		{title: "MapArity", src: `(map (fn [x] x))`, wantErr: parens.ErrArity},
+		{title: "MapNotInvokable", src: `(count (map 1 [1]))`, wantErr: parens.ErrNotInvokable},
+		{title: "Filter", src: `(filter (fn [x] (if x x false)) [1 nil 2 false])`, want: "(1 2)"},
+		{title: "Reduce", src: `(reduce (fn [acc x] (cons x acc)) nil [1 2 3])`, want: "(3 2 1)"},
+		{title: "ReduceNoInit", src: `(reduce (fn [acc x] [acc x]) [1 2 3])`, want: "[[1 2] 3]"},
+		{title: "ReduceSingle", src: `(reduce (fn [acc x] x) [1])`, want: "1"},
+		{title: "ReduceEmpty", src: `(reduce (fn [] :empty) [])`, want: ":empty"},
+		{title: "ReduceArity", src: `(reduce cons)`, wantErr: parens.ErrArity},
+		{title: "ReduceFnError", src: `(reduce first [1 2])`, wantErr: parens.ErrArity},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			env := parens.New(parens.WithGlobals(map[string]parens.Any{
+				"nil":   parens.Nil{},
+				"false": parens.Bool(false),
+			}, nil))
+
+			got, err := evalString(env, tt.src)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %#v", tt.wantErr, err)
+				}
+				return
+			}
+			requireNoErr(t, err)
+
+			s, err := parens.SeqString(parens.NewList(got), "", "", "")
+			requireNoErr(t, err)
+			assertEqual(t, tt.want, s)
+		})
+	}
+}
+
+func TestCoreSeqFns_HostSeq(t *testing.T) {
+	t.Parallel()
+
+	env := parens.New(parens.WithGlobals(map[string]parens.Any{
+		"nums": countdown(3),
+	}, nil))
+
+	got, err := evalString(env, `(reduce (fn [acc x] (cons x acc)) '() (map (fn [x] [x]) nums))`)
+	requireNoErr(t, err)
+
+	s, err := parens.SeqString(got.(parens.Seq), "(", ")", " ")
+	requireNoErr(t, err)
+	assertEqual(t, "([1] [2] [3])", s)
+}
+
+func TestCoreSeqFns_Trace(t *testing.T) {
+	t.Parallel()
+
+	env := parens.New()
+	_, err := evalString(env, `(def f (fn f [x] (first x))) (count (map f [1]))`)
+
+	var pe parens.Error
+	if !errors.As(err, &pe) || !errors.Is(err, parens.ErrNotSeq) {
+		t.Fatalf("expected ErrNotSeq, got %#v", err)
+	}
+
+	trace := pe.StackTrace()
+	if len(trace) < 2 || trace[1].Name != "f" {
+		t.Errorf("expected 'f' in the stack trace, got %v", trace)
+	}
+}
+
+// countdown is a host defined Seq of integers from n down to 1.
+type countdown int
+
+func (c countdown) Count() (int, error) { return int(c), nil }
+
+func (c countdown) First() (parens.Any, error) {
+	if c <= 0 {
+		return nil, nil
+	}
+	return parens.Int64(c), nil
+}
+
+func (c countdown) Next() (parens.Seq, error) {
+	if c <= 1 {
+		return nil, nil
+	}
+	return c - 1, nil
+}
+
+func (c countdown) Conj(items ...parens.Any) (res parens.Seq, err error) {
+	res = c
+	for _, item := range items {
+		if res, err = parens.Cons(item, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
@@ -2,20 +2,29 @@ package parens
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 )
 
+// defaultNS is the namespace used for qualifying symbols in syntax-quoted
+// forms. Symbols qualified with this namespace always resolve to globals.
+const defaultNS = "user"
+
 var _ ConcurrentMap = (*mutexMap)(nil)
 
 // Env represents the environment/context in which forms are evaluated
 // for result. Env is not safe for concurrent use. Use fork() to get a
 // child context for concurrent executions.
 type Env struct {
-	ctx      context.Context
+	state    *evalState
+	realizer *realizer
 	analyzer Analyzer
 	expander Expander
 	globals  ConcurrentMap
-	stack    []stackFrame
+	stack    []StackFrame
+	scope    *scope
 	maxDepth int
 }
 
@@ -47,14 +56,32 @@ func (env *Env) Eval(form Any) (Any, error) {
 	return expr.Eval()
 }
 
-// Resolve a symbol.
+// EvalContext is same as Eval but uses the given context for the duration of
+// the evaluation. Evaluation is aborted with an error wrapping the context
+// error once the context is cancelled or its deadline expires.
+func (env *Env) EvalContext(ctx context.Context, form Any) (Any, error) {
+	prev := env.state.setContext(ctx)
+	defer env.state.setContext(prev)
+
+	return env.Eval(form)
+}
+
+// Context returns the context associated with the env. Long running Invokable
+// implementations should honor cancellation of this context.
+func (env *Env) Context() context.Context { return env.state.context() }
+
+// Resolve a symbol. Local bindings are looked up in the current lexical scope
+// and its parents, before falling back to the global bindings. Symbols that
+// are qualified with the default namespace (e.g., `user/foo`) are resolved
+// directly from the global bindings.
 func (env Env) Resolve(sym string) Any {
-	if len(env.stack) > 0 {
-		// check inside top of the stack for local bindings.
-		top := env.stack[len(env.stack)-1]
-		if v, found := top.Vars[sym]; found {
-			return v
-		}
+	if isQualified(sym) && strings.HasPrefix(sym, defaultNS+"/") {
+		v, _ := env.globals.Load(strings.TrimPrefix(sym, defaultNS+"/"))
+		return v
+	}
+
+	if v, found := env.scope.resolve(sym); found {
+		return v
 	}
 	// return the value from global bindings if found.
 	v, _ := env.globals.Load(sym)
@@ -85,35 +112,243 @@ func (env *Env) expandAnalyze(form Any) (Expr, error) {
 // Fork creates a child context from Env and returns it. The child context
 // can be used as context for an independent thread of execution.
 func (env *Env) Fork() *Env {
+	child := env.forkSeq()
+	child.state = &evalState{ctx: env.Context()}
+	return child
+}
+
+// forkSeq creates a child context for realizing lazy seqs created in env.
+// Unlike Fork, the child shares the evaluation state with env. Hence the
+// realization is subject to the context of the ongoing evaluation and its
+// invocations count towards the max depth of env.
+func (env *Env) forkSeq() *Env {
 	return &Env{
-		ctx:      env.ctx,
+		state:    env.state,
 		globals:  env.globals,
 		expander: env.expander,
 		analyzer: env.analyzer,
+		scope:    env.scope,
 		maxDepth: env.maxDepth,
 	}
 }
 
-func (env *Env) push(frame stackFrame) {
+// withRealizer invokes fn with r as the realizer of env, so that the seqs
+// accessed by the invocations on env are realized on behalf of r.
+func (env *Env) withRealizer(r *realizer, fn func() (Seq, error)) (Seq, error) {
+	prev := env.realizer
+	env.realizer = r
+	defer func() { env.realizer = prev }()
+
+	return fn()
+}
+
+// ctxErr returns an error wrapping the context error if the context is
+// cancelled or expired.
+func (env *Env) ctxErr() error {
+	if err := env.Context().Err(); err != nil {
+		return Error{
+			Cause:   err,
+			Message: "evaluation aborted",
+		}
+	}
+	return nil
+}
+
+// withTrace attaches the current call stack to the error, with the most recent
+// call first, unless the error already carries a stack trace.
+func (env *Env) withTrace(err error) error {
+	var pe Error
+	if errors.As(err, &pe) && len(pe.trace) > 0 {
+		return err
+	}
+
+	e, ok := err.(Error)
+	if !ok {
+		e = Error{Cause: err}
+	}
+
+	e.trace = make([]StackFrame, 0, len(env.stack))
+	for i := len(env.stack) - 1; i >= 0; i-- {
+		e.trace = append(e.trace, env.stack[i])
+	}
+	return e
+}
+
+// WithPos annotates the error with the source position unless the position is
+// unknown or the error is already annotated with a position. Errors of forms
+// that do not carry their source span (e.g., a symbol read at the top level)
+// can be positioned using the spans recorded by the reader.
+func WithPos(err error, pos Position) error {
+	var pe Error
+	if pos.Ln == 0 || (errors.As(err, &pe) && pe.Pos.Ln > 0) {
+		return err
+	}
+
+	e, ok := err.(Error)
+	if !ok {
+		e = Error{Cause: err}
+	}
+	e.Pos = pos
+	return e
+}
+
+func (env *Env) push(frame StackFrame) error {
+	if !env.state.enter(env.maxDepth) {
+		return Error{
+			Cause:   ErrMaxDepthExceeded,
+			Message: fmt.Sprintf("depth %d reached while calling '%s' (%s)", env.maxDepth, frame.Name, env.callChain()),
+		}
+	}
+
 	env.stack = append(env.stack, frame)
+	return nil
 }
 
-func (env *Env) pop() (frame *stackFrame) {
+func (env *Env) pop() (frame *StackFrame) {
 	if len(env.stack) == 0 {
 		panic("pop from empty stack")
 	}
+	env.state.exit()
 	frame, env.stack = &env.stack[len(env.stack)-1], env.stack[:len(env.stack)-1]
 	return frame
 }
 
+// callChain returns a compact representation of the names in the call stack
+// with the most recent call last. Consecutive calls to the same name are
+// collapsed and only the most recent entries are included.
+func (env *Env) callChain() string {
+	const maxEntries = 10
+
+	type entry struct {
+		name  string
+		count int
+	}
+
+	var entries []entry
+	for _, frame := range env.stack {
+		if n := len(entries); n > 0 && entries[n-1].name == frame.Name {
+			entries[n-1].count++
+			continue
+		}
+		entries = append(entries, entry{name: frame.Name, count: 1})
+	}
+
+	var names []string
+	if len(entries) > maxEntries {
+		names = append(names, fmt.Sprintf("... %d more", len(entries)-maxEntries))
+		entries = entries[len(entries)-maxEntries:]
+	}
+
+	for _, e := range entries {
+		if e.count > 1 {
+			names = append(names, fmt.Sprintf("%s (x%d)", e.name, e.count))
+		} else {
+			names = append(names, e.name)
+		}
+	}
+
+	return strings.Join(names, " -> ")
+}
+
+func (env *Env) isSpecialForm(name string) bool {
+	var forms map[string]ParseSpecial
+	switch a := env.analyzer.(type) {
+	case *BuiltinAnalyzer:
+		forms = a.SpecialForms
+	case BuiltinAnalyzer:
+		forms = a.SpecialForms
+	}
+
+	_, found := forms[name]
+	return found
+}
+
 func (env *Env) setGlobal(key string, value Any) {
 	env.globals.Store(key, value)
 }
 
-type stackFrame struct {
+// evalState holds the state of an evaluation that is shared by an Env and
+// the forks realizing lazy seqs on its behalf. evalState is safe for
+// concurrent use since the seqs can be realized from any goroutine.
+type evalState struct {
+	mu    sync.Mutex
+	ctx   context.Context
+	depth int
+}
+
+func (es *evalState) context() context.Context {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return es.ctx
+}
+
+// setContext sets the context of the evaluation and returns the previous one.
+func (es *evalState) setContext(ctx context.Context) context.Context {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	prev := es.ctx
+	es.ctx = ctx
+	return prev
+}
+
+// enter records an invocation unless the depth limit is reached. Returns
+// false if the invocation is not allowed.
+func (es *evalState) enter(maxDepth int) bool {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.depth >= maxDepth {
+		return false
+	}
+	es.depth++
+	return true
+}
+
+func (es *evalState) exit() {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	es.depth--
+}
+
+// StackFrame represents an invocation in the call stack of an Env. Pos is the
+// source position of the invocation form, if known.
+type StackFrame struct {
 	Name string
 	Args []Any
-	Vars map[string]Any
+	Pos  Position
+}
+
+// String returns the invocation rendered as an s-expression. Arguments that
+// are not SExpressable are rendered using their Go representation.
+func (sf StackFrame) String() string {
+	s, err := SeqString(NewList(append([]Any{Symbol(sf.Name)}, sf.Args...)...), "(", ")", " ")
+	if err != nil {
+		return "(" + sf.Name + " ...)"
+	}
+	return s
+}
+
+// scope holds local bindings introduced by fn, let etc. Scopes are chained
+// through parent to form the lexical environment. A scope must not be
+// modified once it is visible to other forms since closures and forked
+// envs may share it.
+type scope struct {
+	parent *scope
+	vars   map[string]Any
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{parent: parent, vars: map[string]Any{}}
+}
+
+func (s *scope) bind(name string, v Any) { s.vars[name] = v }
+
+func (s *scope) resolve(name string) (Any, bool) {
+	for ; s != nil; s = s.parent {
+		if v, found := s.vars[name]; found {
+			return v, true
+		}
+	}
+	return nil, false
 }
 
 func newMutexMap() ConcurrentMap { return &mutexMap{} }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/spy16/parens"
 	"github.com/spy16/parens/repl"
+	"github.com/spy16/parens/stdlib/math"
+	"github.com/spy16/parens/stdlib/strings"
 )
 
 var globals = map[string]parens.Any{
@@ -16,6 +18,12 @@ var globals = map[string]parens.Any{
 }
 
 func main() {
+	for name, fn := range math.Globals() {
+		globals[name] = fn
+	}
+	for name, fn := range strings.Globals() {
+		globals[name] = fn
+	}
 	env := parens.New(parens.WithGlobals(globals, nil))
 
 	r := repl.New(env,
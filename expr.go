@@ -1,6 +1,7 @@
 package parens
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -8,11 +9,15 @@ import (
 
 var (
 	_ Expr = (*ConstExpr)(nil)
+	_ Expr = (*ResolveExpr)(nil)
 	_ Expr = (*DefExpr)(nil)
 	_ Expr = (*QuoteExpr)(nil)
 	_ Expr = (*InvokeExpr)(nil)
 	_ Expr = (*IfExpr)(nil)
 	_ Expr = (*DoExpr)(nil)
+	_ Expr = (*FnExpr)(nil)
+	_ Expr = (*LetExpr)(nil)
+	_ Expr = (*SyntaxQuoteExpr)(nil)
 )
 
 // ConstExpr returns the Const value wrapped inside when evaluated. It has
@@ -22,20 +27,293 @@ type ConstExpr struct{ Const Any }
 // Eval returns the constant value unmodified.
 func (ce ConstExpr) Eval() (Any, error) { return ce.Const, nil }
 
+// ResolveExpr resolves the symbol Name in the Env when evaluated. Symbols
+// within a lexical scope (e.g., in fn and let bodies) are analyzed into a
+// ResolveExpr since the analyzed forms are evaluated again with different
+// bindings.
+type ResolveExpr struct {
+	Env  *Env
+	Name string
+}
+
+// Eval returns the value bound to the Name. Returns error with ErrNotFound
+// cause if the Name is not bound.
+func (re ResolveExpr) Eval() (Any, error) {
+	v := re.Env.Resolve(re.Name)
+	if v == nil {
+		return nil, Error{
+			Cause:   ErrNotFound,
+			Message: re.Name,
+		}
+	}
+	return v, nil
+}
+
+// VectorExpr represents a vector literal. Items are evaluated in order and
+// the results are returned as a new vector with the Meta, if any.
+type VectorExpr struct {
+	Items []Expr
+	Meta  *Map
+}
+
+// Eval evaluates the items and returns a vector of the results.
+func (ve VectorExpr) Eval() (Any, error) {
+	vals := make([]Any, 0, len(ve.Items))
+	for _, item := range ve.Items {
+		v, err := item.Eval()
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, v)
+	}
+
+	vec := NewVector(vals...)
+	if ve.Meta != nil {
+		return vec.WithMeta(ve.Meta), nil
+	}
+	return vec, nil
+}
+
+// MapExpr represents a map literal. Keys and values are evaluated in order
+// and the results are returned as a new map with the Meta, if any. Returns
+// error with ErrDuplicateKey cause if two keys evaluate to the same value.
+// Pos is the source position of the map literal, if known.
+type MapExpr struct {
+	Keys, Vals []Expr
+	Meta       *Map
+	Pos        Position
+}
+
+// Eval evaluates the keys and values and returns a map of the results.
+func (me MapExpr) Eval() (Any, error) {
+	m := &Map{}
+	for i, keyExpr := range me.Keys {
+		key, err := keyExpr.Eval()
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := me.Vals[i].Eval()
+		if err != nil {
+			return nil, err
+		}
+
+		if found, err := m.HasKey(key); err != nil {
+			return nil, err
+		} else if found {
+			s, _ := toSExpr(key)
+			return nil, Error{Cause: ErrDuplicateKey, Message: s, Pos: me.Pos}
+		}
+
+		if m, err = m.Assoc(key, val); err != nil {
+			return nil, err
+		}
+	}
+
+	if me.Meta != nil {
+		return m.WithMeta(me.Meta), nil
+	}
+	return m, nil
+}
+
+// SetExpr represents a set literal. Items are evaluated in order and the
+// results are returned as a new set with the Meta, if any. Returns error with
+// ErrDuplicateKey cause if two items evaluate to the same value. Pos is the
+// source position of the set literal, if known.
+type SetExpr struct {
+	Items []Expr
+	Meta  *Map
+	Pos   Position
+}
+
+// Eval evaluates the items and returns a set of the results.
+func (se SetExpr) Eval() (Any, error) {
+	set := &Set{}
+	for _, item := range se.Items {
+		v, err := item.Eval()
+		if err != nil {
+			return nil, err
+		}
+
+		if found, err := set.Contains(v); err != nil {
+			return nil, err
+		} else if found {
+			s, _ := toSExpr(v)
+			return nil, Error{Cause: ErrDuplicateKey, Message: s, Pos: se.Pos}
+		}
+
+		res, err := set.Conj(v)
+		if err != nil {
+			return nil, err
+		}
+		set = res.(*Set)
+	}
+
+	if se.Meta != nil {
+		return set.WithMeta(se.Meta), nil
+	}
+	return set, nil
+}
+
 // QuoteExpr expression represents a quoted form and
 type QuoteExpr struct{ Form Any }
 
 // Eval returns the quoted form unmodified.
-func (qe QuoteExpr) Eval() (Any, error) {
-	// TODO: re-use this for syntax-quote and unquote?
-	return qe.Form, nil
+func (qe QuoteExpr) Eval() (Any, error) { return qe.Form, nil }
+
+// SyntaxQuoteExpr represents a syntax-quoted template form. Symbols in the
+// template are namespace-qualified, (unquote x) forms are replaced with the
+// result of evaluating x and (unquote-splicing x) forms are replaced by the
+// items of the sequence x evaluates to. Symbols ending with '#' (e.g., `x#`)
+// are replaced by a generated symbol unique to each evaluation, but the same
+// within the template.
+type SyntaxQuoteExpr struct {
+	Env  *Env
+	Form Any
+}
+
+// Eval returns a new form built from the template.
+func (se SyntaxQuoteExpr) Eval() (Any, error) {
+	return se.expand(se.Form, map[Symbol]Symbol{})
+}
+
+func (se SyntaxQuoteExpr) expand(form Any, gensyms map[Symbol]Symbol) (Any, error) {
+	switch f := form.(type) {
+	case Symbol:
+		if isQualified(string(f)) || f == "&" || se.Env.isSpecialForm(string(f)) {
+			return f, nil
+		} else if len(f) > 1 && strings.HasSuffix(string(f), "#") {
+			if _, found := gensyms[f]; !found {
+				gensyms[f] = gensym(strings.TrimSuffix(string(f), "#")+"__", "__auto__")
+			}
+			return gensyms[f], nil
+		}
+		return Symbol(defaultNS + "/" + string(f)), nil
+
+	case MetaSymbol:
+		sym, err := se.expand(f.Symbol, gensyms)
+		if err != nil {
+			return nil, err
+		}
+		return MergeMeta(sym, f.Meta())
+
+	case *Vector:
+		items, err := se.expandItems(f, gensyms)
+		if err != nil {
+			return nil, err
+		}
+		return NewVector(items...), nil
+
+	case *Map:
+		res := &Map{}
+		err := f.each(func(key, val Any) (err error) {
+			if key, err = se.expand(key, gensyms); err != nil {
+				return err
+			} else if val, err = se.expand(val, gensyms); err != nil {
+				return err
+			}
+			res, err = res.Assoc(key, val)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+
+	case *Set:
+		items, err := se.expandItems(f, gensyms)
+		if err != nil {
+			return nil, err
+		}
+		return NewSet(items...)
+
+	case String:
+		return f, nil
+
+	case Seq:
+		if arg, ok := unquoted(f, "unquote"); ok {
+			return se.Env.Eval(arg)
+		} else if _, ok := unquoted(f, "unquote-splicing"); ok {
+			return nil, Error{
+				Cause:   errors.New("invalid unquote-splicing form"),
+				Message: "unquote-splicing is allowed only within a list or vector",
+			}
+		}
+
+		items, err := se.expandItems(f, gensyms)
+		if err != nil {
+			return nil, err
+		}
+		return NewList(items...), nil
+	}
+
+	return form, nil
+}
+
+// expandItems expands each item of the seq, splicing the (unquote-splicing x)
+// items in place.
+func (se SyntaxQuoteExpr) expandItems(seq Seq, gensyms map[Symbol]Symbol) ([]Any, error) {
+	var items []Any
+	err := ForEach(seq, func(item Any) (bool, error) {
+		arg, ok := unquoted(item, "unquote-splicing")
+		if !ok {
+			v, err := se.expand(item, gensyms)
+			items = append(items, v)
+			return err != nil, err
+		}
+
+		v, err := se.Env.Eval(arg)
+		if err != nil || IsNil(v) {
+			return err != nil, err
+		}
+
+		splice, ok := v.(Seq)
+		if !ok {
+			return true, Error{
+				Cause:   errors.New("invalid unquote-splicing form"),
+				Message: fmt.Sprintf("cannot splice value of type '%s'", reflect.TypeOf(v)),
+			}
+		}
+
+		spliced, err := toSlice(splice)
+		items = append(items, spliced...)
+		return err != nil, err
+	})
+	return items, err
+}
+
+// unquoted returns the argument if the form is of the form (name arg).
+func unquoted(form Any, name string) (Any, bool) {
+	seq, ok := form.(Seq)
+	if _, isVec := form.(*Vector); !ok || isVec {
+		return nil, false
+	}
+
+	if cnt, err := seq.Count(); err != nil || cnt != 2 {
+		return nil, false
+	}
+
+	if first, err := seq.First(); err != nil || first != Symbol(name) {
+		return nil, false
+	}
+
+	rest, err := seq.Next()
+	if err != nil {
+		return nil, false
+	}
+
+	arg, err := rest.First()
+	return arg, err == nil
 }
 
-// DefExpr creates a global binding with the Name when evaluated.
+// DefExpr creates a global binding with the Name when evaluated. If Meta is
+// set (e.g., from the metadata of the name symbol), it is merged into the
+// metadata of the value if the value implements WithMeta.
 type DefExpr struct {
 	Env   *Env
 	Name  string
 	Value Expr
+	Meta  *Map
 }
 
 // Eval creates a symbol binding in the global (root) stack frame.
@@ -50,6 +328,12 @@ func (de DefExpr) Eval() (Any, error) {
 		return nil, err
 	}
 
+	if _, ok := val.(WithMeta); ok && de.Meta != nil {
+		if val, err = MergeMeta(val, de.Meta); err != nil {
+			return nil, err
+		}
+	}
+
 	de.Env.setGlobal(de.Name, val)
 	return Symbol(de.Name), nil
 }
@@ -76,8 +360,12 @@ func (ife IfExpr) Eval() (Any, error) {
 	return target.Eval()
 }
 
-// DoExpr represents the (do expr*) form.
-type DoExpr struct{ Exprs []Expr }
+// DoExpr represents the (do expr*) form. If Env is set, evaluation is aborted
+// when the context of the Env is done.
+type DoExpr struct {
+	Env   *Env
+	Exprs []Expr
+}
 
 // Eval the expression
 func (de DoExpr) Eval() (Any, error) {
@@ -85,6 +373,12 @@ func (de DoExpr) Eval() (Any, error) {
 	var err error
 
 	for _, expr := range de.Exprs {
+		if de.Env != nil {
+			if err = de.Env.ctxErr(); err != nil {
+				return nil, err
+			}
+		}
+
 		res, err = expr.Eval()
 		if err != nil {
 			return nil, err
@@ -97,10 +391,87 @@ func (de DoExpr) Eval() (Any, error) {
 	return res, nil
 }
 
-// InvokeExpr performs invocation of target when evaluated.
+// FnExpr creates a function value when evaluated. Lexical scope at the time
+// of evaluation is captured by the function.
+type FnExpr struct {
+	Env     *Env
+	Name    string
+	Macro   bool
+	Methods []FnMethod
+	Meta    *Map
+}
+
+// Eval returns a new Fn value closing over the current local bindings, with
+// the Meta, if any.
+func (fe FnExpr) Eval() (Any, error) {
+	return &Fn{
+		Name:    fe.Name,
+		Macro:   fe.Macro,
+		Methods: fe.Methods,
+		scope:   fe.Env.scope,
+		meta:    fe.Meta,
+	}, nil
+}
+
+// LetExpr binds each name to the result of evaluating the corresponding value
+// in a new lexical scope and evaluates the body in that scope. Each binding is
+// visible to the values that follow it.
+type LetExpr struct {
+	Env    *Env
+	Names  []string
+	Values []Expr
+	Body   []Expr
+}
+
+// Eval the expression
+func (le LetExpr) Eval() (Any, error) {
+	prev := le.Env.scope
+	defer func() { le.Env.scope = prev }()
+
+	for i, name := range le.Names {
+		v, err := le.Values[i].Eval()
+		if err != nil {
+			return nil, err
+		}
+
+		s := newScope(le.Env.scope)
+		s.bind(name, v)
+		le.Env.scope = s
+	}
+
+	return DoExpr{Env: le.Env, Exprs: le.Body}.Eval()
+}
+
+// LazySeqExpr creates a LazySeq when evaluated. The body is evaluated in the
+// lexical scope at the time of evaluation when the seq is first accessed, on a
+// fork of the Env so that the seq can be realized from any goroutine. The fork
+// shares the context and the stack depth of the ongoing evaluation of the Env.
+// The result of the body must be a Seq or nil.
+type LazySeqExpr struct {
+	Env  *Env
+	Body []Any
+}
+
+// Eval returns a new LazySeq closing over the current local bindings.
+func (le LazySeqExpr) Eval() (Any, error) {
+	env := le.Env.forkSeq()
+	return &LazySeq{fn: func(r *realizer) (Seq, error) {
+		return env.withRealizer(r, func() (Seq, error) {
+			res, err := evalBody(env, le.Body)
+			if err != nil {
+				return nil, err
+			}
+			return toSeq("lazy-seq", res)
+		})
+	}}, nil
+}
+
+// InvokeExpr performs invocation of target when evaluated. Pos is the source
+// position of the invocation form, if known.
 type InvokeExpr struct {
 	Env    *Env
 	Name   string
+	Pos    Position
 	Target Expr
 	Args   []Expr
 }
@@ -117,9 +488,14 @@ func (ie InvokeExpr) Eval() (Any, error) {
 		return nil, Error{
 			Cause:   ErrNotInvokable,
 			Message: fmt.Sprintf("value of type '%s' is not invokable", reflect.TypeOf(val)),
+			Pos:     ie.Pos,
 		}
 	}
 
+	if err := ie.Env.ctxErr(); err != nil {
+		return nil, err
+	}
+
 	var args []Any
 	for _, ae := range ie.Args {
 		v, err := ae.Eval()
@@ -129,14 +505,23 @@ func (ie InvokeExpr) Eval() (Any, error) {
 		args = append(args, v)
 	}
 
-	ie.Env.push(stackFrame{
+	if err := ie.Env.push(StackFrame{
 		Name: ie.Name,
 		Args: args,
-		Vars: map[string]Any{},
-	})
+		Pos:  ie.Pos,
+	}); err != nil {
+		return nil, WithPos(err, ie.Pos)
+	}
 	defer ie.Env.pop()
 
-	return fn.Invoke(ie.Env, args...)
+	res, err := fn.Invoke(ie.Env, args...)
+	if err != nil {
+		// attach the stack at the point of failure. errors from nested
+		// invocations already carry a trace and position and are returned
+		// as is.
+		return nil, WithPos(ie.Env.withTrace(err), ie.Pos)
+	}
+	return res, nil
 }
 
 // GoExpr evaluates an expression in a separate goroutine.
@@ -1,6 +1,8 @@
 package parens_test
 
 import (
+	"context"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
@@ -114,6 +116,334 @@ func TestQuoteExpr_Eval(t *testing.T) {
 	assertEqual(t, want, got)
 }
 
+func TestLetExpr_Eval(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    parens.Any
+		wantErr bool
+	}{
+		{
+			title: "Empty Bindings",
+			src:   `(let () :body)`,
+			want:  parens.Keyword("body"),
+		},
+		{
+			title: "Empty Body",
+			src:   `(let (a 1))`,
+			want:  parens.Nil{},
+		},
+		{
+			title: "Sequential Bindings",
+			src:   `(let (a 1 b a) b)`,
+			want:  parens.Int64(1),
+		},
+		{
+			title: "Shadowing",
+			src:   `(let (a 1) (let (a 2) a))`,
+			want:  parens.Int64(2),
+		},
+		{
+			title: "Inner Scope Does Not Leak",
+			src:   `(let (a 1) (let (a 2) a) a)`,
+			want:  parens.Int64(1),
+		},
+		{
+			title: "Nested Closure Sees Outer Locals",
+			src:   `(let (a :outer) (((fn () (let (b :inner) (fn () a))))))`,
+			want:  parens.Keyword("outer"),
+		},
+		{
+			title:   "Binding Not Visible After Let",
+			src:     `(let (a 1) a) a`,
+			wantErr: true,
+		},
+		{
+			title:   "Odd Bindings",
+			src:     `(let (a) a)`,
+			wantErr: true,
+		},
+		{
+			title:   "Invalid Binding Name",
+			src:     `(let (1 2) 1)`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := evalString(parens.New(), tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Eval() error = %#v, wantErr %#v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assertEqual(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSyntaxQuoteExpr_Eval(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    parens.Any
+		wantErr bool
+	}{
+		{
+			title: "Self Evaluating",
+			src:   "`:key",
+			want:  parens.Keyword("key"),
+		},
+		{
+			title: "Symbol Is Qualified",
+			src:   "`foo",
+			want:  parens.Symbol("user/foo"),
+		},
+		{
+			title: "Special Forms And Qualified Symbols Are Not Qualified",
+			src:   "`(if other/foo &)",
+			want:  parens.NewList(parens.Symbol("if"), parens.Symbol("other/foo"), parens.Symbol("&")),
+		},
+		{
+			title: "Unquote",
+			src:   "(let (a 1) `(foo ~a))",
+			want:  parens.NewList(parens.Symbol("user/foo"), parens.Int64(1)),
+		},
+		{
+			title: "Unquote Splicing",
+			src:   "(let (a '(1 2)) `(foo ~@a 3))",
+			want:  parens.NewList(parens.Symbol("user/foo"), parens.Int64(1), parens.Int64(2), parens.Int64(3)),
+		},
+		{
+			title: "Unquote Splicing Nil",
+			src:   "`(foo ~@nil)",
+			want:  parens.NewList(parens.Symbol("user/foo")),
+		},
+		{
+			title: "Nested Lists",
+			src:   "(let (a 1) `(do (quote ~a)))",
+			want: parens.NewList(
+				parens.Symbol("do"),
+				parens.NewList(parens.Symbol("quote"), parens.Int64(1)),
+			),
+		},
+		{
+			title: "Qualified Symbol Resolves To Global",
+			src:   "(def foo :global) (let (foo :local) (eval-form `foo))",
+			want:  parens.Keyword("global"),
+		},
+		{
+			title:   "Splice Outside List",
+			src:     "`~@(quote (1 2))",
+			wantErr: true,
+		},
+		{
+			title:   "Splice Non Sequence",
+			src:     "`(foo ~@1)",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			env := parens.New(parens.WithGlobals(map[string]parens.Any{
+				"eval-form": parens.GoFunc(func(env *parens.Env, args ...parens.Any) (parens.Any, error) {
+					return env.Eval(args[0])
+				}),
+			}, nil))
+
+			got, err := evalString(env, tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Eval() error = %#v, wantErr %#v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assertEqual(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSyntaxQuoteExpr_Eval_AutoGensym(t *testing.T) {
+	env := parens.New()
+
+	form, err := evalString(env, "`(let (x# 1) x#)")
+	requireNoErr(t, err)
+
+	items := seqItems(t, form.(parens.Seq))
+	bindings := seqItems(t, items[1].(parens.Seq))
+	if bindings[0] != items[2] {
+		t.Errorf("expected same gensym within template, got %v and %v", bindings[0], items[2])
+	}
+	if sym := bindings[0].(parens.Symbol); !strings.HasPrefix(string(sym), "x__") || !strings.HasSuffix(string(sym), "__auto__") {
+		t.Errorf("unexpected gensym: %v", sym)
+	}
+
+	again, err := evalString(env, "`x#")
+	requireNoErr(t, err)
+	if again == bindings[0] {
+		t.Errorf("expected unique gensym for each evaluation, got %v twice", again)
+	}
+
+	res, err := env.Eval(form)
+	requireNoErr(t, err)
+	assertEqual(t, parens.Int64(1), res)
+}
+
+func TestInvokeExpr_Eval_MaxDepth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Exceeded", func(t *testing.T) {
+		env := parens.New(parens.WithMaxDepth(50))
+
+		_, err := evalString(env, `(def loop (fn (n) (loop n))) (loop 1)`)
+		if !errors.Is(err, parens.ErrMaxDepthExceeded) {
+			t.Fatalf("expected ErrMaxDepthExceeded, got %#v", err)
+		}
+		if !strings.Contains(err.Error(), "loop (x50)") {
+			t.Errorf("expected call chain in error, got %q", err.Error())
+		}
+
+		// stack must be unwound and the env usable after the failure.
+		res, err := evalString(env, `((fn () :ok))`)
+		requireNoErr(t, err)
+		assertEqual(t, parens.Keyword("ok"), res)
+	})
+
+	t.Run("Through Lazy Seqs", func(t *testing.T) {
+		for _, src := range []string{
+			`(def f (fn [n] (first (lazy-seq (cons (f n) nil))))) (f 1)`,
+			`(def f (fn [n] (first (map f [n])))) (f 1)`,
+			`(def f (fn [n] (first (filter f [n])))) (f 1)`,
+			`(def f (fn [n] (first (rest (iterate f n))))) (f 1)`,
+		} {
+			_, err := evalString(parens.New(parens.WithMaxDepth(200)), src)
+			if !errors.Is(err, parens.ErrMaxDepthExceeded) {
+				t.Errorf("%s: expected ErrMaxDepthExceeded, got %#v", src, err)
+			}
+		}
+	})
+
+	t.Run("Default Limit", func(t *testing.T) {
+		_, err := evalString(parens.New(), `(def loop (fn (n) (loop n))) (loop 1)`)
+		if !errors.Is(err, parens.ErrMaxDepthExceeded) {
+			t.Fatalf("expected ErrMaxDepthExceeded, got %#v", err)
+		}
+	})
+}
+
+func TestEnv_EvalContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Cancelled Before Eval", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		env := parens.New()
+		_, err := env.EvalContext(ctx, parens.NewList(parens.NewList(parens.Symbol("fn"), parens.NewList())))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %#v", err)
+		}
+
+		// context is restored after EvalContext returns.
+		_, err = evalString(env, `((fn ()))`)
+		requireNoErr(t, err)
+	})
+
+	t.Run("Cancelled During Eval", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		called := false
+		env := parens.New(
+			parens.WithContext(ctx),
+			parens.WithGlobals(map[string]parens.Any{
+				"cancel": parens.GoFunc(func(_ *parens.Env, _ ...parens.Any) (parens.Any, error) {
+					cancel()
+					return nil, nil
+				}),
+				"called": parens.GoFunc(func(_ *parens.Env, _ ...parens.Any) (parens.Any, error) {
+					called = true
+					return nil, nil
+				}),
+			}, nil),
+		)
+
+		_, err := evalString(env, `(do (cancel) (called))`)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %#v", err)
+		}
+		if called {
+			t.Errorf("expected evaluation to abort after cancellation")
+		}
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		env := parens.New(parens.WithMaxDepth(1 << 30))
+		forms, err := reader.New(strings.NewReader(`(def f (fn () (f))) (f)`)).All()
+		requireNoErr(t, err)
+
+		_, err = env.EvalContext(ctx, forms[0])
+		requireNoErr(t, err)
+
+		_, err = env.EvalContext(ctx, forms[1])
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded, got %#v", err)
+		}
+	})
+
+	t.Run("Seq Created Under Cancelled Context", func(t *testing.T) {
+		env := parens.New()
+		forms, err := reader.New(strings.NewReader(`
+(def s (lazy-seq (cons :a nil)))
+(def m (map (fn [x] x) [:b]))
+[(first s) (first m)]`)).All()
+		requireNoErr(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		for _, form := range forms[:2] {
+			_, err = env.EvalContext(ctx, form)
+			requireNoErr(t, err)
+		}
+		cancel()
+
+		// realization uses the context of the evaluation forcing the seqs.
+		res, err := env.EvalContext(context.Background(), forms[2])
+		requireNoErr(t, err)
+		got, err := toString(res)
+		requireNoErr(t, err)
+		assertEqual(t, "[:a :b]", got)
+	})
+
+	t.Run("Deadline During Seq Walk", func(t *testing.T) {
+		for _, src := range []string{
+			`(count (range 1000000000))`,
+			`(count (map (fn [x] x) (range 1000000000)))`,
+			`(reduce (fn [acc x] x) (range 1000000000))`,
+		} {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+
+			form, err := reader.New(strings.NewReader(src)).One()
+			requireNoErr(t, err)
+
+			_, err = parens.New().EvalContext(ctx, form)
+			cancel()
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("%s: expected context.DeadlineExceeded, got %#v", src, err)
+			}
+		}
+	})
+}
+
 func TestGoExpr_Eval(t *testing.T) {
 	r := reader.New(strings.NewReader("(go (def test :keyword))"))
 	actual, err := r.One()
@@ -135,6 +465,15 @@ func TestGoExpr_Eval(t *testing.T) {
 	}
 }
 
+func seqItems(t *testing.T, seq parens.Seq) []parens.Any {
+	var items []parens.Any
+	requireNoErr(t, parens.ForEach(seq, func(item parens.Any) (bool, error) {
+		items = append(items, item)
+		return false, nil
+	}))
+	return items
+}
+
 func requireNoErr(t *testing.T, err error) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -0,0 +1,203 @@
+package parens
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	_ Invokable    = (*Fn)(nil)
+	_ Invokable    = GoFunc(nil)
+	_ SExpressable = (*Fn)(nil)
+)
+
+// Fn represents a function defined in lisp using the `fn` special form. Fn
+// captures the lexical scope at the point of definition and makes it visible
+// to the body when invoked. A function can have multiple arities (Methods),
+// the one matching the number of arguments is selected during invocation.
+// If Macro is true, the function is invoked by the Expander with unevaluated
+// forms as arguments and the result replaces the original form.
+type Fn struct {
+	Name    string
+	Macro   bool
+	Methods []FnMethod
+
+	scope *scope
+	meta  *Map
+}
+
+// FnMethod represents a single arity of a function. If Rest is not empty,
+// the method is variadic and all the arguments after the fixed Params are
+// bound to Rest as a list.
+type FnMethod struct {
+	Params []string
+	Rest   string
+	Body   []Any
+
+	// analyzed holds the *fnBody of the last invocation if the method is
+	// created by the fn special form.
+	analyzed *atomic.Value
+}
+
+// fnBody is the body of a method analyzed against the env.
+type fnBody struct {
+	env   *Env
+	exprs []Expr
+}
+
+// Variadic returns true if the method accepts rest arguments.
+func (m FnMethod) Variadic() bool { return m.Rest != "" }
+
+// Invoke selects the method matching the number of arguments, binds the args
+// to the parameters in a new scope enclosed by the captured one and then
+// evaluates the body forms in order. Result of the last form is returned.
+func (fn *Fn) Invoke(env *Env, args ...Any) (Any, error) {
+	method, err := fn.selectMethod(len(args))
+	if err != nil {
+		return nil, err
+	}
+
+	s := newScope(fn.scope)
+	if fn.Name != "" {
+		// allow named functions to refer to themselves.
+		s.bind(fn.Name, fn)
+	}
+
+	for i, param := range method.Params {
+		s.bind(param, args[i])
+	}
+
+	if method.Variadic() {
+		s.bind(method.Rest, NewList(args[len(method.Params):]...))
+	}
+
+	prev := env.scope
+	env.scope = s
+	defer func() { env.scope = prev }()
+
+	body, err := method.analyze(env)
+	if err != nil {
+		return nil, err
+	}
+	return DoExpr{Env: env, Exprs: body}.Eval()
+}
+
+// Meta returns the metadata of the function.
+func (fn *Fn) Meta() *Map { return fn.meta }
+
+// WithMeta returns a copy of the function with the given metadata.
+func (fn *Fn) WithMeta(meta *Map) Any {
+	res := *fn
+	res.meta = meta
+	return &res
+}
+
+// GoFunc implements Invokable using a native Go function value.
+type GoFunc func(env *Env, args ...Any) (Any, error)
+
+// Invoke simply calls the wrapped function value with the arguments.
+func (fn GoFunc) Invoke(env *Env, args ...Any) (Any, error) { return fn(env, args...) }
+
+// SExpr returns a valid s-expression representing the function.
+func (fn *Fn) SExpr() (string, error) {
+	var b strings.Builder
+	b.WriteString("(fn")
+	if fn.Name != "" {
+		b.WriteString(" " + fn.Name)
+	}
+
+	for _, m := range fn.Methods {
+		s, err := m.sexpr()
+		if err != nil {
+			return "", err
+		}
+
+		if len(fn.Methods) > 1 {
+			s = "(" + s + ")"
+		}
+		b.WriteString(" " + s)
+	}
+	b.WriteString(")")
+
+	return b.String(), nil
+}
+
+func (fn *Fn) selectMethod(argc int) (*FnMethod, error) {
+	var variadic *FnMethod
+	for i, m := range fn.Methods {
+		if m.Variadic() {
+			variadic = &fn.Methods[i]
+		} else if len(m.Params) == argc {
+			return &fn.Methods[i], nil
+		}
+	}
+
+	if variadic != nil && argc >= len(variadic.Params) {
+		return variadic, nil
+	}
+
+	name := fn.Name
+	if name == "" {
+		name = "fn"
+	}
+
+	return nil, Error{
+		Cause:   ErrArity,
+		Message: fmt.Sprintf("%d argument(s) passed to %s", argc, name),
+	}
+}
+
+// checkArity returns an error with ErrArity cause if the number of args is
+// not exactly n.
+func checkArity(name string, args []Any, n int) error {
+	if len(args) != n {
+		return Error{
+			Cause:   ErrArity,
+			Message: fmt.Sprintf("%s requires %d argument(s), got %d", name, n, len(args)),
+		}
+	}
+	return nil
+}
+
+// analyze returns the body of the method analyzed against the env. The body is
+// expanded and analyzed once and reused by the later invocations on the same
+// env. Exprs are bound to the env they are analyzed against, hence invoking on
+// a different env (e.g., the fork realizing a lazy seq) analyzes the body again.
+// Analysis is deferred to the invocation so that the body can refer to globals
+// defined after the function.
+func (m *FnMethod) analyze(env *Env) ([]Expr, error) {
+	if m.analyzed != nil {
+		if body, ok := m.analyzed.Load().(*fnBody); ok && body.env == env {
+			return body.exprs, nil
+		}
+	}
+
+	exprs, err := analyzeBody(env, m.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.analyzed != nil {
+		m.analyzed.Store(&fnBody{env: env, exprs: exprs})
+	}
+	return exprs, nil
+}
+
+func (m FnMethod) sexpr() (string, error) {
+	params := append([]string(nil), m.Params...)
+	if m.Variadic() {
+		params = append(params, "&", m.Rest)
+	}
+
+	body, err := SeqString(NewList(m.Body...), "", "", " ")
+	if err != nil {
+		return "", err
+	}
+
+	s := "(" + strings.Join(params, " ") + ")"
+	if body != "" {
+		s += " " + body
+	}
+	return s, nil
+}
@@ -0,0 +1,239 @@
+package parens_test
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spy16/parens"
+	"github.com/spy16/parens/reader"
+)
+
+func TestFn_Invoke(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    parens.Any
+		wantErr bool
+	}{
+		{
+			title: "No Params",
+			src:   `((fn () :hello))`,
+			want:  parens.Keyword("hello"),
+		},
+		{
+			title: "Empty Body",
+			src:   `((fn ()))`,
+			want:  parens.Nil{},
+		},
+		{
+			title: "Params Bound",
+			src:   `((fn (a b) b) 1 2)`,
+			want:  parens.Int64(2),
+		},
+		{
+			title: "Closure",
+			src:   `(((fn (a) (fn (b) a)) :outer) :inner)`,
+			want:  parens.Keyword("outer"),
+		},
+		{
+			title: "Named Self Reference",
+			src:   `((fn self (a) (if a (self nil) :done)) true)`,
+			want:  parens.Keyword("done"),
+		},
+		{
+			title: "Def And Call",
+			src:   `(def f (fn (x) x)) (f :value)`,
+			want:  parens.Keyword("value"),
+		},
+		{
+			title: "Args Of Each Call",
+			src:   `(def f (fn (x) (let (y x) y))) (f 1) (f 2)`,
+			want:  parens.Int64(2),
+		},
+		{
+			title: "Redefined Global",
+			src:   `(def v 1) (def f (fn () v)) (f) (def v 2) (f)`,
+			want:  parens.Int64(2),
+		},
+		{
+			title: "Forward Reference",
+			src:   `(def f (fn () (g))) (def g (fn () :g)) (f)`,
+			want:  parens.Keyword("g"),
+		},
+		{
+			title:   "Wrong Number Of Args",
+			src:     `((fn (a) a))`,
+			wantErr: true,
+		},
+		{
+			title: "Multi Arity",
+			src:   `(def f (fn ((a) :one) ((a b) :two))) (f 1 2)`,
+			want:  parens.Keyword("two"),
+		},
+		{
+			title: "Variadic",
+			src:   `((fn (a & more) more) 1 2 3)`,
+			want:  parens.NewList(parens.Int64(2), parens.Int64(3)),
+		},
+		{
+			title: "Variadic Without Rest Args",
+			src:   `((fn (a & more) more) 1)`,
+			want:  parens.NewList(),
+		},
+		{
+			title: "Fixed Arity Preferred Over Variadic",
+			src:   `((fn ((a) :fixed) ((a & more) :variadic)) 1)`,
+			want:  parens.Keyword("fixed"),
+		},
+		{
+			title:   "No Matching Arity",
+			src:     `((fn ((a) :one) ((a b) :two)))`,
+			wantErr: true,
+		},
+		{
+			title:   "Duplicate Arity",
+			src:     `(fn ((a) :one) ((b) :two))`,
+			wantErr: true,
+		},
+		{
+			title:   "Multiple Variadic Arities",
+			src:     `(fn ((& a) :one) ((b & c) :two))`,
+			wantErr: true,
+		},
+		{
+			title:   "Invalid Rest Param",
+			src:     `(fn (a & b c) a)`,
+			wantErr: true,
+		},
+		{
+			title:   "Missing Params",
+			src:     `(fn)`,
+			wantErr: true,
+		},
+		{
+			title:   "Invalid Param",
+			src:     `(fn (1) 1)`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := evalString(parens.New(), tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Eval() error = %#v, wantErr %#v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assertEqual(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFn_Invoke_ExpandsOnce(t *testing.T) {
+	t.Parallel()
+
+	var expansions int
+	env := parens.New(parens.WithGlobals(map[string]parens.Any{
+		"expanded": parens.GoFunc(func(_ *parens.Env, _ ...parens.Any) (parens.Any, error) {
+			expansions++
+			return parens.Nil{}, nil
+		}),
+	}, nil))
+
+	got, err := evalString(env, `
+(defmacro m (x) (expanded) x)
+(def f (fn (a) (let (b (m a)) (m b))))
+(f 1) (f 2) (f 3)`)
+	requireNoErr(t, err)
+	assertEqual(t, parens.Int64(3), got)
+	assertEqual(t, 2, expansions)
+}
+
+func TestFn_Invoke_ExpandsOncePerSeq(t *testing.T) {
+	t.Parallel()
+
+	var expansions int32
+	env := parens.New(parens.WithGlobals(map[string]parens.Any{
+		"expanded": parens.GoFunc(func(_ *parens.Env, _ ...parens.Any) (parens.Any, error) {
+			atomic.AddInt32(&expansions, 1)
+			return parens.Nil{}, nil
+		}),
+	}, nil))
+
+	_, err := evalString(env, `(defmacro m (x) (expanded) x) (def f (fn (a) (m a)))`)
+	requireNoErr(t, err)
+
+	for _, src := range []string{
+		`(count (map f (range 1000)))`,
+		`(count (filter f (range 1000)))`,
+		`(count (take 1000 (iterate f 0)))`,
+	} {
+		atomic.StoreInt32(&expansions, 0)
+		_, err := evalString(env, src)
+		requireNoErr(t, err)
+		assertEqual(t, int32(1), atomic.LoadInt32(&expansions))
+	}
+}
+
+func TestFn_SExpr(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Single Arity", func(t *testing.T) {
+		fn := &parens.Fn{
+			Name: "id",
+			Methods: []parens.FnMethod{
+				{Params: []string{"x"}, Body: []parens.Any{parens.Symbol("x")}},
+			},
+		}
+
+		got, err := fn.SExpr()
+		requireNoErr(t, err)
+		assertEqual(t, "(fn id (x) x)", got)
+	})
+
+	t.Run("Multi Arity", func(t *testing.T) {
+		fn := &parens.Fn{
+			Methods: []parens.FnMethod{
+				{Body: []parens.Any{parens.Nil{}}},
+				{Params: []string{"x"}, Rest: "more", Body: []parens.Any{parens.Symbol("more")}},
+			},
+		}
+
+		got, err := fn.SExpr()
+		requireNoErr(t, err)
+		assertEqual(t, "(fn (() nil) ((x & more) more))", got)
+	})
+}
+
+// evalString reads all forms from src and evaluates them in order against
+// env. Result of the last form is returned.
+func evalString(env *parens.Env, src string) (parens.Any, error) {
+	rd := reader.New(strings.NewReader(src))
+	forms, err := rd.All()
+	if err != nil {
+		return nil, err
+	}
+
+	var res parens.Any
+	for i, form := range forms {
+		if res, err = env.Eval(form); err != nil {
+			return nil, parens.WithPos(err, rd.Spans()[i].Begin)
+		}
+	}
+	return res, nil
+}
+
+func TestFn_Invoke_ArityError(t *testing.T) {
+	fn := &parens.Fn{Name: "f", Methods: []parens.FnMethod{{Params: []string{"a"}}}}
+
+	_, err := fn.Invoke(parens.New())
+	if !errors.Is(err, parens.ErrArity) {
+		t.Errorf("expected ErrArity, got %#v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package parens
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// type tags mixed into hashes so that values of different types with the
+// same underlying representation (e.g., Symbol "a" and String "a") do not
+// collide.
+const (
+	hashTagNumber byte = iota + 1
+	hashTagString
+	hashTagSymbol
+	hashTagKeyword
+	hashTagNil
+	hashTagBool
+	hashTagChar
+	hashTagList
+	hashTagVector
+	hashTagMap
+	hashTagSet
+	hashTagRegex
+)
+
+// Hashable values can be used as keys of a Map and as items of a Set. Values
+// that are equal as per Eq must return the same hash. All the builtin values
+// implement Hashable; collections hash by their content. Host types can be
+// used as keys by implementing Hashable along with EqualityProvider.
+type Hashable interface {
+	Hash() (uint64, error)
+}
+
+// Hash returns the hash of the value. Go nil is hashed as Nil. Returns error
+// with ErrNotHashable cause if the value does not implement Hashable.
+func Hash(v Any) (uint64, error) {
+	if v == nil {
+		return Nil{}.Hash()
+	}
+
+	h, ok := v.(Hashable)
+	if !ok {
+		return 0, Error{
+			Cause:   ErrNotHashable,
+			Message: fmt.Sprintf("value of type '%s'", reflect.TypeOf(v)),
+		}
+	}
+	return h.Hash()
+}
+
+// hashString returns the FNV-1a hash of s along with the type tag.
+func hashString(tag byte, s string) uint64 {
+	h := uint64(fnvOffset64)
+	h = (h ^ uint64(tag)) * fnvPrime64
+	for i := 0; i < len(s); i++ {
+		h = (h ^ uint64(s[i])) * fnvPrime64
+	}
+	return h
+}
+
+// hashUint64 returns the FNV-1a hash of the bytes of v along with the type
+// tag.
+func hashUint64(tag byte, v uint64) uint64 {
+	h := uint64(fnvOffset64)
+	h = (h ^ uint64(tag)) * fnvPrime64
+	for i := 0; i < 8; i++ {
+		h = (h ^ (v & 0xff)) * fnvPrime64
+		v >>= 8
+	}
+	return h
+}
+
+// hashOrdered returns the hash of a sequence of values where the order of the
+// values is significant.
+func hashOrdered(tag byte, seq Seq) (uint64, error) {
+	h := uint64(fnvOffset64)
+	h = (h ^ uint64(tag)) * fnvPrime64
+	err := ForEach(seq, func(item Any) (bool, error) {
+		ih, err := Hash(item)
+		h = h*31 + ih
+		return err != nil, err
+	})
+	return h, err
+}
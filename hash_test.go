@@ -0,0 +1,177 @@
+package parens_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/spy16/parens"
+)
+
+func TestHash(t *testing.T) {
+	t.Parallel()
+
+	list := func(items ...parens.Any) parens.Any { return parens.NewList(items...) }
+	next := func(seq parens.Seq) parens.Any {
+		res, err := seq.Next()
+		requireNoErr(t, err)
+		return res
+	}
+	lazy := func(items ...parens.Any) parens.Any {
+		return parens.NewLazySeq(func() (parens.Seq, error) { return parens.NewList(items...), nil })
+	}
+
+	for _, tt := range []struct {
+		desc     string
+		a, b     parens.Any
+		sameHash bool
+	}{
+		{desc: "nil", a: parens.Nil{}, b: nil, sameHash: true},
+		{desc: "int64", a: parens.Int64(10), b: parens.Int64(10), sameHash: true},
+		{desc: "float64", a: parens.Float64(1.5), b: parens.Float64(1.5), sameHash: true},
+		{desc: "signed zero", a: parens.Float64(0), b: parens.Float64(math.Copysign(0, -1)), sameHash: true},
+		{desc: "bool", a: parens.Bool(true), b: parens.Bool(true), sameHash: true},
+		{desc: "char", a: parens.Char('λ'), b: parens.Char('λ'), sameHash: true},
+		{desc: "string", a: parens.String("a"), b: parens.String("a"), sameHash: true},
+		{desc: "keyword", a: parens.Keyword("a"), b: parens.Keyword("a"), sameHash: true},
+		{desc: "symbol", a: parens.Symbol("a"), b: parens.Symbol("a"), sameHash: true},
+		{
+			desc:     "list",
+			a:        list(parens.Int64(1), list(parens.Keyword("a"))),
+			b:        list(parens.Int64(1), list(parens.Keyword("a"))),
+			sameHash: true,
+		},
+		{desc: "empty list", a: parens.NewList(), b: &parens.LinkedList{}, sameHash: true},
+		{
+			desc:     "vector",
+			a:        parens.NewVector(parens.Int64(1), parens.NewVector()),
+			b:        parens.NewVector(parens.Int64(1), parens.NewVector()),
+			sameHash: true,
+		},
+		{
+			desc:     "vector seq",
+			a:        next(parens.NewVector(parens.Int64(1), parens.Int64(2), parens.Int64(3))),
+			b:        next(parens.NewVector(parens.Int64(0), parens.Int64(2), parens.Int64(3))),
+			sameHash: true,
+		},
+		{
+			desc:     "vector seq and list",
+			a:        next(parens.NewVector(parens.Int64(1), parens.Int64(2), parens.Int64(3))),
+			b:        list(parens.Int64(2), parens.Int64(3)),
+			sameHash: true,
+		},
+		{
+			desc:     "lazy seq and list",
+			a:        lazy(parens.Int64(1), parens.Int64(2)),
+			b:        list(parens.Int64(1), parens.Int64(2)),
+			sameHash: true,
+		},
+		{
+			desc:     "lazy seq and vector seq",
+			a:        lazy(parens.Int64(2), parens.Int64(3)),
+			b:        next(parens.NewVector(parens.Int64(1), parens.Int64(2), parens.Int64(3))),
+			sameHash: true,
+		},
+		{
+			desc:     "set seq",
+			a:        next(mustSet(t, parens.Int64(1), parens.Int64(2))),
+			b:        next(mustSet(t, parens.Int64(1), parens.Int64(2))),
+			sameHash: true,
+		},
+		{
+			desc:     "map in different order",
+			a:        mustMap(t, parens.Keyword("a"), parens.Int64(1), parens.Keyword("b"), parens.Int64(2)),
+			b:        mustMap(t, parens.Keyword("b"), parens.Int64(2), parens.Keyword("a"), parens.Int64(1)),
+			sameHash: true,
+		},
+		{
+			desc:     "set in different order",
+			a:        mustSet(t, parens.Int64(1), parens.Int64(2), parens.Int64(3)),
+			b:        mustSet(t, parens.Int64(3), parens.Int64(1), parens.Int64(2)),
+			sameHash: true,
+		},
+		{desc: "int64 and float64", a: parens.Int64(1), b: parens.Float64(1), sameHash: true},
+		{desc: "bigint and int64", a: parens.NewBigInt(big.NewInt(7)), b: parens.Int64(7), sameHash: true},
+		{desc: "ratio and float64", a: parens.NewRatio(big.NewRat(1, 4)), b: parens.Float64(0.25), sameHash: true},
+		{desc: "int64 and float64 differ", a: parens.Int64(1), b: parens.Float64(1.5)},
+		{desc: "string and symbol", a: parens.String("a"), b: parens.Symbol("a")},
+		{desc: "symbol and keyword", a: parens.Symbol("a"), b: parens.Keyword("a")},
+		{desc: "nil and false", a: parens.Nil{}, b: parens.Bool(false)},
+		{desc: "list order", a: list(parens.Int64(1), parens.Int64(2)), b: list(parens.Int64(2), parens.Int64(1))},
+		{desc: "list and vector", a: list(parens.Int64(1)), b: parens.NewVector(parens.Int64(1))},
+		{
+			desc: "vector seq and vector",
+			a:    next(parens.NewVector(parens.Int64(1), parens.Int64(2))),
+			b:    parens.NewVector(parens.Int64(2)),
+		},
+		{
+			desc: "map values",
+			a:    mustMap(t, parens.Keyword("a"), parens.Int64(1)),
+			b:    mustMap(t, parens.Keyword("a"), parens.Int64(2)),
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			eq, err := parens.Eq(tt.a, tt.b)
+			requireNoErr(t, err)
+			if eq != tt.sameHash {
+				t.Fatalf("Eq() = %t, want %t", eq, tt.sameHash)
+			}
+
+			ha, err := parens.Hash(tt.a)
+			requireNoErr(t, err)
+
+			hb, err := parens.Hash(tt.b)
+			requireNoErr(t, err)
+
+			if (ha == hb) != tt.sameHash {
+				t.Errorf("Hash() a = %d, b = %d, want same = %t", ha, hb, tt.sameHash)
+			}
+		})
+	}
+}
+
+func TestHash_NotHashable(t *testing.T) {
+	t.Parallel()
+
+	_, err := parens.Hash(parens.NewVector(parens.Int64(1), struct{}{}))
+	if !errors.Is(err, parens.ErrNotHashable) {
+		t.Errorf("expected ErrNotHashable, got %#v", err)
+	}
+}
+
+func TestHash_HostType(t *testing.T) {
+	t.Parallel()
+
+	env := parens.New(parens.WithGlobals(map[string]parens.Any{
+		"p1": point{X: 1, Y: 2},
+		"p2": point{X: 1, Y: 2},
+	}, nil))
+
+	got, err := evalString(env, `{p1 :a, [p1] :b}`)
+	requireNoErr(t, err)
+
+	m := got.(*parens.Map)
+	for key, want := range map[parens.Any]parens.Any{
+		point{X: 1, Y: 2}:                   parens.Keyword("a"),
+		parens.NewVector(point{X: 1, Y: 2}): parens.Keyword("b"),
+	} {
+		v, found, err := m.Get(key)
+		requireNoErr(t, err)
+		if !found || v != want {
+			t.Errorf("Get(%v) = (%v, %t), want (%v, true)", key, v, found, want)
+		}
+	}
+
+	_, err = evalString(env, `#{p1 p2}`)
+	if !errors.Is(err, parens.ErrDuplicateKey) {
+		t.Errorf("expected ErrDuplicateKey, got %#v", err)
+	}
+}
+
+// point is a host type usable as a map key.
+type point struct{ X, Y int }
+
+func (p point) Hash() (uint64, error) { return uint64(p.X)*31 + uint64(p.Y), nil }
+
+func (p point) Equals(other parens.Any) (bool, error) { return p == other, nil }
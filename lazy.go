@@ -0,0 +1,316 @@
+package parens
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	_ Seq          = (*LazySeq)(nil)
+	_ Hashable     = (*LazySeq)(nil)
+	_ SExpressable = (*LazySeq)(nil)
+)
+
+// LazySeq is a Seq whose content is produced by a function on first access.
+// The function is invoked at most once and the result is cached. LazySeq is
+// safe for concurrent use: accessing the seq while the function is running
+// waits for the result. Infinite sequences can be built by returning a seq
+// whose rest is another LazySeq. Accessing the seq during its realization by
+// the evaluation realizing it (e.g., a lazy-seq body referring to the seq)
+// returns error with ErrRealizing cause. Functions given to NewLazySeq must
+// not access the seq they produce.
+type LazySeq struct {
+	mu       sync.Mutex
+	fn       func(r *realizer) (Seq, error)
+	done     chan struct{}
+	owner    *realizer
+	seq      Seq
+	err      error
+	infinite bool
+}
+
+// realizer identifies a thread of execution realizing lazy seqs. A realization
+// passes its realizer to the realizations it triggers, so that a seq accessed
+// again by its own realization can be told apart from a concurrent access.
+type realizer struct {
+	_ byte // non-zero size so that every realizer is distinct.
+}
+
+// NewLazySeq returns a LazySeq realized by invoking fn. fn can return nil to
+// indicate an empty seq.
+func NewLazySeq(fn func() (Seq, error)) *LazySeq {
+	return &LazySeq{fn: func(*realizer) (Seq, error) { return fn() }}
+}
+
+// NewInfiniteSeq returns a LazySeq realized by invoking fn that is known to
+// never end. Count, SExpr and Hash of a seq found to be infinite return error
+// with ErrInfiniteSeq cause instead of realizing the seq.
+func NewInfiniteSeq(fn func() (Seq, error)) *LazySeq {
+	ls := NewLazySeq(fn)
+	ls.infinite = true
+	return ls
+}
+
+// Infinite returns true if the seq is known to never end.
+func (ls *LazySeq) Infinite() bool { return ls.infinite }
+
+// Realize invokes the function producing the seq if not done already and
+// returns the result. Callers accessing the seq while the function is running
+// wait for the result.
+func (ls *LazySeq) Realize() (Seq, error) { return ls.realize(nil) }
+
+// realize is same as Realize but on behalf of the realizer r, which is nil if
+// the caller is not realizing a seq. If the seq is being realized by r itself,
+// waiting would deadlock. Hence, an error is returned instead.
+func (ls *LazySeq) realize(r *realizer) (Seq, error) {
+	ls.mu.Lock()
+	if ls.fn == nil {
+		defer ls.mu.Unlock()
+		return ls.seq, ls.err
+	} else if ls.done != nil {
+		done, owner := ls.done, ls.owner
+		ls.mu.Unlock()
+
+		if r != nil && r == owner {
+			return nil, Error{
+				Cause:   ErrRealizing,
+				Message: "lazy seq accessed while being realized",
+			}
+		}
+		<-done
+		return ls.realize(r)
+	}
+
+	if r == nil {
+		r = &realizer{}
+	}
+	fn := ls.fn
+	ls.done, ls.owner = make(chan struct{}), r
+	ls.mu.Unlock()
+
+	seq, err := fn(r)
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.seq, ls.err, ls.fn, ls.owner = seq, err, nil, nil
+	close(ls.done)
+	return ls.seq, ls.err
+}
+
+// First realizes the seq and returns the first item.
+func (ls *LazySeq) First() (Any, error) {
+	seq, err := ls.Realize()
+	if err != nil || seq == nil {
+		return nil, err
+	}
+	return seq.First()
+}
+
+// Next realizes the seq and returns the rest of the items.
+func (ls *LazySeq) Next() (Seq, error) {
+	seq, err := ls.Realize()
+	if err != nil || seq == nil {
+		return nil, err
+	}
+	return seq.Next()
+}
+
+// Count realizes the entire seq and returns the number of items.
+func (ls *LazySeq) Count() (int, error) { return countSeq(nil, ls) }
+
+// Conj returns a new list with all the items added at the head of the seq.
+func (ls *LazySeq) Conj(items ...Any) (res Seq, err error) {
+	res = ls
+	for _, item := range items {
+		if res, err = Cons(item, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// SExpr realizes the entire seq and returns it as a list s-expression.
+// Returns error if the seq is infinite.
+func (ls *LazySeq) SExpr() (string, error) {
+	if _, err := ls.Count(); err != nil {
+		return "", err
+	}
+	return SeqString(ls, "(", ")", " ")
+}
+
+// Hash realizes the entire seq and returns a hash computed from the items.
+// Lazy seqs and lists with the same items have the same hash. Returns error
+// if the seq is infinite.
+func (ls *LazySeq) Hash() (uint64, error) {
+	if _, err := ls.Count(); err != nil {
+		return 0, err
+	}
+	return hashOrdered(hashTagList, ls)
+}
+
+// Equals returns true if the other value is a LinkedList, a LazySeq or a seq
+// of a vector or a map and contains the same values.
+func (ls *LazySeq) Equals(other Any) (bool, error) {
+	var o Seq
+	switch v := other.(type) {
+	case *LazySeq:
+		if ls.infinite && v.infinite {
+			return false, infiniteErr("equals")
+		}
+		o = v
+	case *LinkedList:
+		o = v
+	case *vectorSeq:
+		o = v
+	case *mapSeq:
+		o = v
+	default:
+		return false, nil
+	}
+
+	return seqEquals(ls, o)
+}
+
+// seqEquals compares the items of the seqs in order.
+func seqEquals(s, o Seq) (bool, error) {
+	for {
+		a, err := firstOf(nil, s)
+		if err != nil {
+			return false, err
+		}
+
+		b, err := firstOf(nil, o)
+		if err != nil {
+			return false, err
+		}
+
+		if a == nil || b == nil {
+			return a == nil && b == nil, nil
+		}
+
+		if eq, err := Eq(a, b); err != nil || !eq {
+			return false, err
+		}
+
+		if s, err = s.Next(); err != nil {
+			return false, err
+		}
+
+		if o, err = o.Next(); err != nil {
+			return false, err
+		}
+	}
+}
+
+// countSeq counts the items of the seq by walking through the lazy seqs and
+// the lists with unknown count. Returns error with ErrInfiniteSeq cause if
+// an infinite seq is found. If env is not nil, the seqs are realized on behalf
+// of the env and the walk is aborted once the env context is cancelled or
+// expires.
+func countSeq(env *Env, seq Seq) (int, error) {
+	var r *realizer
+	if env != nil {
+		r = env.realizer
+	}
+
+	n := 0
+	for seq != nil {
+		if env != nil {
+			if err := env.ctxErr(); err != nil {
+				return 0, err
+			}
+		}
+
+		switch s := seq.(type) {
+		case *LazySeq:
+			if s.infinite {
+				return 0, infiniteErr("count")
+			}
+
+			var err error
+			if seq, err = s.realize(r); err != nil {
+				return 0, err
+			}
+
+		case *LinkedList:
+			if s == nil {
+				return n, nil
+			} else if s.count >= 0 {
+				return n + s.count, nil
+			}
+			n++
+			seq = s.rest
+
+		default:
+			cnt, err := s.Count()
+			return n + cnt, err
+		}
+	}
+	return n, nil
+}
+
+// isLazy returns true if the seq is a LazySeq or a list with a lazy rest.
+func isLazy(seq Seq) bool {
+	switch s := seq.(type) {
+	case *LazySeq:
+		return true
+	case *LinkedList:
+		return s.lazy()
+	}
+	return false
+}
+
+// isInfinite returns true if the seq is a LazySeq known to never end.
+func isInfinite(seq Seq) bool {
+	ls, ok := seq.(*LazySeq)
+	return ok && ls.infinite
+}
+
+// firstOf returns the first item of the seq on behalf of the realizer r.
+func firstOf(r *realizer, seq Seq) (Any, error) {
+	seq, err := realizeAll(r, seq)
+	if err != nil {
+		return nil, err
+	}
+
+	if seq == nil {
+		return nil, nil
+	}
+	return seq.First()
+}
+
+// realizeAll realizes the seq on behalf of the realizer r until the result is
+// not a LazySeq.
+func realizeAll(r *realizer, seq Seq) (Seq, error) {
+	for {
+		ls, ok := seq.(*LazySeq)
+		if !ok {
+			return seq, nil
+		}
+
+		var err error
+		if seq, err = ls.realize(r); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// nextOf returns the rest of the seq on behalf of the realizer r.
+func nextOf(r *realizer, seq Seq) (Seq, error) {
+	seq, err := realizeAll(r, seq)
+	if err != nil {
+		return nil, err
+	}
+
+	if seq == nil {
+		return nil, nil
+	}
+	return seq.Next()
+}
+
+func infiniteErr(op string) error {
+	return Error{
+		Cause:   ErrInfiniteSeq,
+		Message: fmt.Sprintf("cannot %s an infinite seq", op),
+	}
+}
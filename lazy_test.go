@@ -0,0 +1,286 @@
+package parens_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spy16/parens"
+)
+
+func TestLazySeq(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    string
+		wantErr error
+	}{
+		{title: "LazySeq", src: `(lazy-seq (cons 1 nil))`, want: "(1)"},
+		{title: "LazySeqEmpty", src: `(lazy-seq nil)`, want: "()"},
+		{title: "LazySeqClosure", src: `(let [x 1] (lazy-seq (cons x nil)))`, want: "(1)"},
+		{
+			title: "LazySeqRecursive",
+			src:   `(def from (fn from [n] (lazy-seq (cons n (from (succ n)))))) (take 3 (from 1))`,
+			want:  "(1 2 3)",
+		},
+		{title: "LazySeqNotSeq", src: `(count (lazy-seq 1))`, wantErr: parens.ErrNotSeq},
+		{title: "LazySeqSelfRef", src: `(def s (lazy-seq (cons 1 (rest s)))) (first s)`, wantErr: parens.ErrRealizing},
+		{
+			title:   "LazySeqSelfRefThroughMap",
+			src:     `(def s (lazy-seq (cons (first (map succ s)) nil))) (first s)`,
+			wantErr: parens.ErrRealizing,
+		},
+		{
+			title:   "LazySeqSelfRefThroughReduce",
+			src:     `(def s (lazy-seq (cons (reduce (fn [acc x] x) (take 2 s)) nil))) (first s)`,
+			wantErr: parens.ErrRealizing,
+		},
+		{title: "RangeEnd", src: `(range 3)`, want: "(0 1 2)"},
+		{title: "RangeStartEnd", src: `(range 1 4)`, want: "(1 2 3)"},
+		{title: "RangeStep", src: `(range 10 0 -3)`, want: "(10 7 4 1)"},
+		{title: "RangeEmpty", src: `(range 0)`, want: "()"},
+		{title: "RangeZeroStep", src: `(range 0 1 0)`, wantErr: errors.New("invalid range")},
+		{title: "RangeNotInt", src: `(range :a)`, wantErr: errors.New("invalid range")},
+		{title: "Infinite", src: `(take 3 (range))`, want: "(0 1 2)"},
+		{title: "Iterate", src: `(take 3 (iterate succ 0))`, want: "(0 1 2)"},
+		{title: "Drop", src: `(drop 2 [1 2 3])`, want: "(3)"},
+		{title: "DropAll", src: `(drop 5 [1])`, want: "()"},
+		{title: "DropInfinite", src: `(take 2 (drop 3 (range)))`, want: "(3 4)"},
+		{title: "TakeMore", src: `(take 5 '(1 2))`, want: "(1 2)"},
+		{title: "TakeNotInt", src: `(take :a [1])`, wantErr: errors.New("invalid take")},
+		{title: "MapInfinite", src: `(take 2 (map succ (range)))`, want: "(1 2)"},
+		{title: "FilterInfinite", src: `(take 2 (filter odd? (range)))`, want: "(1 3)"},
+		{title: "ConcatInfinite", src: `(take 3 (concat [:a] (range)))`, want: "(:a 0 1)"},
+		{title: "ConsInfinite", src: `(first (rest (cons :a (range))))`, want: "0"},
+		{title: "CountLazy", src: `(count (cons :a (take 3 (range))))`, want: "4"},
+		{title: "CountInfinite", src: `(count (range))`, wantErr: parens.ErrInfiniteSeq},
+		{title: "CountIterate", src: `(count (iterate succ 0))`, wantErr: parens.ErrInfiniteSeq},
+		{title: "CountMapInfinite", src: `(count (map succ (range)))`, wantErr: parens.ErrInfiniteSeq},
+		{title: "CountConsInfinite", src: `(count (cons 1 (range)))`, wantErr: parens.ErrInfiniteSeq},
+		{title: "CountDropInfinite", src: `(count (drop 2 (range)))`, wantErr: parens.ErrInfiniteSeq},
+		{title: "ReduceLazy", src: `(reduce (fn [acc x] x) (take 3 (range)))`, want: "2"},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			env := parens.New(parens.WithGlobals(map[string]parens.Any{
+				"nil":  parens.Nil{},
+				"succ": parens.GoFunc(succ),
+				"odd?": parens.GoFunc(func(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+					return parens.Bool(args[0].(parens.Int64)%2 != 0), nil
+				}),
+			}, nil))
+
+			got, err := evalString(env, tt.src)
+			if err == nil {
+				got, err = toString(got)
+			}
+
+			if tt.wantErr != nil {
+				// ad-hoc causes are matched by message.
+				if err == nil || !(errors.Is(err, tt.wantErr) || strings.Contains(err.Error(), tt.wantErr.Error())) {
+					t.Fatalf("expected error %v, got %#v", tt.wantErr, err)
+				}
+				return
+			}
+			requireNoErr(t, err)
+			assertEqual(t, tt.want, got)
+		})
+	}
+}
+
+func TestLazySeq_RealizedOnce(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	env := parens.New(parens.WithGlobals(map[string]parens.Any{
+		"tick": parens.GoFunc(func(_ *parens.Env, _ ...parens.Any) (parens.Any, error) {
+			calls++
+			return parens.NewList(parens.Int64(calls)), nil
+		}),
+	}, nil))
+
+	got, err := evalString(env, `(def s (lazy-seq (tick))) [(first s) (count s) (first s)]`)
+	requireNoErr(t, err)
+
+	s, err := toString(got)
+	requireNoErr(t, err)
+	assertEqual(t, "[1 1 1]", s)
+	assertEqual(t, 1, calls)
+}
+
+func TestLazySeq_Equals(t *testing.T) {
+	t.Parallel()
+
+	lazy := func(items ...parens.Any) parens.Seq {
+		return parens.NewLazySeq(func() (parens.Seq, error) { return parens.NewList(items...), nil })
+	}
+
+	for _, tt := range []struct {
+		desc string
+		a, b parens.Any
+		want bool
+	}{
+		{desc: "lazy and list", a: lazy(parens.Int64(1)), b: parens.NewList(parens.Int64(1)), want: true},
+		{desc: "list and lazy", a: parens.NewList(parens.Int64(1)), b: lazy(parens.Int64(1)), want: true},
+		{desc: "empty", a: lazy(), b: parens.NewList(), want: true},
+		{desc: "numeric tower", a: lazy(parens.Int64(1)), b: lazy(parens.NewBigInt(big.NewInt(1))), want: true},
+		{desc: "different items", a: lazy(parens.Int64(1)), b: lazy(parens.Int64(2))},
+		{desc: "different lengths", a: lazy(parens.Int64(1)), b: lazy(parens.Int64(1), parens.Int64(2))},
+		{desc: "vector", a: lazy(parens.Int64(1)), b: parens.NewVector(parens.Int64(1))},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			eq, err := parens.Eq(tt.a, tt.b)
+			requireNoErr(t, err)
+			assertEqual(t, tt.want, eq)
+
+			if tt.want {
+				ha, err := parens.Hash(tt.a)
+				requireNoErr(t, err)
+
+				hb, err := parens.Hash(tt.b)
+				requireNoErr(t, err)
+				assertEqual(t, ha, hb)
+			}
+		})
+	}
+}
+
+func TestLazySeq_Infinite(t *testing.T) {
+	t.Parallel()
+
+	var ones func() (parens.Seq, error)
+	ones = func() (parens.Seq, error) {
+		return parens.Cons(parens.Int64(1), parens.NewInfiniteSeq(ones))
+	}
+	seq := parens.NewInfiniteSeq(ones)
+
+	if _, err := seq.SExpr(); !errors.Is(err, parens.ErrInfiniteSeq) {
+		t.Errorf("SExpr(): expected ErrInfiniteSeq, got %#v", err)
+	}
+
+	if _, err := parens.Hash(seq); !errors.Is(err, parens.ErrInfiniteSeq) {
+		t.Errorf("Hash(): expected ErrInfiniteSeq, got %#v", err)
+	}
+
+	v, err := seq.First()
+	requireNoErr(t, err)
+	assertEqual(t, parens.Int64(1), v)
+}
+
+func succ(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	return args[0].(parens.Int64) + 1, nil
+}
+
+func toString(v parens.Any) (string, error) {
+	return parens.SeqString(parens.NewList(v), "", "", "")
+}
+
+func TestLazySeq_RealizeConcurrently(t *testing.T) {
+	t.Parallel()
+
+	env := parens.New(parens.WithGlobals(map[string]parens.Any{
+		"succ": parens.GoFunc(succ),
+	}, nil))
+
+	var seqs []parens.Seq
+	for _, src := range []string{
+		`(map succ (range 100))`,
+		`(filter succ (range 100))`,
+		`(take 100 (iterate succ 0))`,
+		`(let [x 1] (lazy-seq (cons (succ x) nil)))`,
+	} {
+		got, err := evalString(env, src)
+		requireNoErr(t, err)
+		seqs = append(seqs, got.(parens.Seq))
+	}
+
+	done := make(chan error, len(seqs))
+	for _, seq := range seqs {
+		go func(seq parens.Seq) {
+			_, err := seq.Count()
+			done <- err
+		}(seq)
+	}
+
+	_, err := evalString(env, `(reduce (fn [acc x] (succ x)) (range 100))`)
+	requireNoErr(t, err)
+
+	for range seqs {
+		requireNoErr(t, <-done)
+	}
+}
+
+func TestLazySeq_ConcurrentReaders(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	seq := parens.NewLazySeq(func() (parens.Seq, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return parens.NewList(parens.Keyword("a")), nil
+	})
+
+	const readers = 4
+	done := make(chan error, readers)
+	for i := 0; i < readers; i++ {
+		go func() {
+			v, err := seq.First()
+			if err == nil && v != parens.Keyword("a") {
+				err = fmt.Errorf("expected :a, got %#v", v)
+			}
+			done <- err
+		}()
+	}
+
+	for i := 0; i < readers; i++ {
+		requireNoErr(t, <-done)
+	}
+	assertEqual(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestLazySeq_ConcurrentWrappedReaders(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	env := parens.New(parens.WithGlobals(map[string]parens.Any{
+		"succ": parens.GoFunc(succ),
+		"slow-range": parens.GoFunc(func(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(20 * time.Millisecond)
+			return parens.NewList(args...), nil
+		}),
+	}, nil))
+
+	_, err := evalString(env, `(def s (lazy-seq (slow-range 1 2 3)))`)
+	requireNoErr(t, err)
+
+	srcs := []string{
+		`(count (map succ s))`,
+		`(count (filter succ s))`,
+		`(count (take 5 s))`,
+		`(reduce (fn [acc x] (succ acc)) 0 (map succ s))`,
+	}
+
+	done := make(chan error, len(srcs))
+	for _, src := range srcs {
+		go func(env *parens.Env, src string) {
+			got, err := evalString(env, src)
+			if err == nil && got != parens.Int64(3) {
+				err = fmt.Errorf("%s: expected 3, got %#v", src, got)
+			}
+			done <- err
+		}(env.Fork(), src)
+	}
+
+	for range srcs {
+		requireNoErr(t, <-done)
+	}
+	assertEqual(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,612 @@
+package parens
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"reflect"
+	"strings"
+)
+
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+var (
+	_ Any              = (*Map)(nil)
+	_ Seq              = (*Map)(nil)
+	_ SExpressable     = (*Map)(nil)
+	_ EqualityProvider = (*Map)(nil)
+	_ Hashable         = (*Map)(nil)
+	_ Positional       = (*Map)(nil)
+
+	_ Seq              = (*mapSeq)(nil)
+	_ EqualityProvider = (*mapSeq)(nil)
+	_ Hashable         = (*mapSeq)(nil)
+)
+
+// Map is an immutable, persistent hash map implemented as a hash array mapped
+// trie (HAMT). Keys must implement Hashable. All the updates return a new map
+// sharing structure with the original. As a Seq, Map is a sequence of [key val]
+// vectors in an unspecified but stable order. Zero value is an empty map ready
+// for use.
+type Map struct {
+	count int
+	root  *hamtNode
+	meta  *Map
+	span  Span
+}
+
+// hamtNode is a node in the trie. Bitmap marks which of the 32 slots at this
+// level are present and entries holds only the present slots in order.
+type hamtNode struct {
+	bitmap  uint32
+	entries []hamtEntry
+}
+
+// hamtEntry is either a sub-trie (node is not nil) or a bucket of key-value
+// pairs whose keys all have the same hash.
+type hamtEntry struct {
+	hash uint64
+	kvs  []mapEntry
+	node *hamtNode
+}
+
+type mapEntry struct{ key, val Any }
+
+// NewPositionalMap is same as NewMap but annotates the map with the source
+// span of the map literal it was read from.
+func NewPositionalMap(span Span, kvs ...Any) (*Map, error) {
+	m, err := NewMap(kvs...)
+	if err != nil {
+		return nil, err
+	}
+	m.span = span
+	return m, nil
+}
+
+// Span returns the source span of the map. Maps that are not read from source
+// or are derived from another map have no span.
+func (m *Map) Span() Span {
+	if m == nil {
+		return Span{}
+	}
+	return m.span
+}
+
+// NewMap returns a new map containing given key-value pairs. Later pairs
+// override earlier pairs with the same key.
+func NewMap(kvs ...Any) (*Map, error) {
+	if len(kvs)%2 != 0 {
+		return nil, errors.New("expecting even number of key-value forms")
+	}
+
+	var err error
+	m := &Map{}
+	for i := 0; i < len(kvs); i += 2 {
+		if m, err = m.Assoc(kvs[i], kvs[i+1]); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Size returns the number of key-value pairs in the map.
+func (m *Map) Size() int {
+	if m == nil {
+		return 0
+	}
+	return m.count
+}
+
+// Get returns the value associated with the key. found is false if the key is
+// not present in the map.
+func (m *Map) Get(key Any) (val Any, found bool, err error) {
+	if m.Size() == 0 {
+		return nil, false, nil
+	}
+
+	hash, err := Hash(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	node := m.root
+	for shift := uint(0); ; shift += hamtBits {
+		bit := bitFor(hash, shift)
+		if node.bitmap&bit == 0 {
+			return nil, false, nil
+		}
+
+		e := node.entries[node.index(bit)]
+		if e.node != nil {
+			node = e.node
+			continue
+		} else if e.hash != hash {
+			return nil, false, nil
+		}
+
+		i, err := e.find(key)
+		if err != nil || i < 0 {
+			return nil, false, err
+		}
+		return e.kvs[i].val, true, nil
+	}
+}
+
+// HasKey returns true if the key is present in the map.
+func (m *Map) HasKey(key Any) (bool, error) {
+	_, found, err := m.Get(key)
+	return found, err
+}
+
+// Assoc returns a new map with the key associated with the val.
+func (m *Map) Assoc(key, val Any) (*Map, error) {
+	hash, err := Hash(key)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &hamtNode{}
+	if m != nil && m.root != nil {
+		root = m.root
+	}
+
+	root, added, err := root.assoc(0, hash, key, val)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Map{count: m.Size(), root: root, meta: m.Meta()}
+	if added {
+		res.count++
+	}
+	return res, nil
+}
+
+// Meta returns the metadata of the map.
+func (m *Map) Meta() *Map {
+	if m == nil {
+		return nil
+	}
+	return m.meta
+}
+
+// WithMeta returns a copy of the map with the given metadata.
+func (m *Map) WithMeta(meta *Map) Any {
+	res := &Map{}
+	if m != nil {
+		*res = *m
+	}
+	res.meta = meta
+	return res
+}
+
+// Dissoc returns a new map without the key. Returns the map itself if the key
+// is not present.
+func (m *Map) Dissoc(key Any) (*Map, error) {
+	hash, err := Hash(key)
+	if err != nil {
+		return nil, err
+	} else if m.Size() == 0 {
+		return m, nil
+	}
+
+	root, removed, err := m.root.dissoc(0, hash, key)
+	if err != nil {
+		return nil, err
+	} else if !removed {
+		return m, nil
+	}
+	return &Map{count: m.count - 1, root: root, meta: m.meta}, nil
+}
+
+// SExpr returns a valid s-expression for Map.
+func (m *Map) SExpr() (string, error) {
+	var b strings.Builder
+	b.WriteString("{")
+
+	first := true
+	err := m.each(func(key, val Any) error {
+		if !first {
+			b.WriteString(", ")
+		}
+		first = false
+
+		k, err := toSExpr(key)
+		if err != nil {
+			return err
+		}
+
+		v, err := toSExpr(val)
+		if err != nil {
+			return err
+		}
+
+		b.WriteString(k + " " + v)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+// Equals returns true if the other value is also a Map and contains the same
+// key-value pairs.
+func (m *Map) Equals(other Any) (bool, error) {
+	o, ok := other.(*Map)
+	if !ok || o.Size() != m.Size() {
+		return false, nil
+	}
+
+	err := m.each(func(key, val Any) error {
+		v, found, err := o.Get(key)
+		if err != nil {
+			return err
+		} else if !found {
+			return errStopIteration
+		}
+
+		eq, err := Eq(val, v)
+		if err != nil {
+			return err
+		} else if !eq {
+			return errStopIteration
+		}
+		return nil
+	})
+
+	if err == errStopIteration {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Hash returns a hash computed from the key-value pairs of the map. The hash
+// does not depend on the order of the pairs.
+func (m *Map) Hash() (uint64, error) {
+	var sum uint64
+	err := m.each(func(key, val Any) error {
+		hk, err := Hash(key)
+		if err != nil {
+			return err
+		}
+
+		hv, err := Hash(val)
+		sum += hk*31 + hv
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return hashUint64(hashTagMap, sum), nil
+}
+
+// Count returns the number of key-value pairs in the map.
+func (m *Map) Count() (int, error) { return m.Size(), nil }
+
+// First returns the first [key val] entry of the map. Returns nil if the map
+// is empty.
+func (m *Map) First() (Any, error) {
+	if m.Size() == 0 {
+		return nil, nil
+	}
+
+	node := m.root
+	for node.entries[0].node != nil {
+		node = node.entries[0].node
+	}
+
+	kv := node.entries[0].kvs[0]
+	return NewVector(kv.key, kv.val), nil
+}
+
+// Next returns a sequence of all the [key val] entries of the map except the
+// first.
+func (m *Map) Next() (Seq, error) {
+	if m.Size() == 0 {
+		return nil, nil
+	}
+	return newMapSeq(m, false).Next()
+}
+
+// Conj returns a new map with the entries added. Each entry must be a vector
+// of the form [key val].
+func (m *Map) Conj(items ...Any) (Seq, error) {
+	res := m
+	for _, item := range items {
+		vec, ok := item.(*Vector)
+		if !ok || vec.Size() != 2 {
+			return nil, Error{
+				Cause:   errors.New("invalid map entry"),
+				Message: fmt.Sprintf("expecting [key val] vector, not '%s'", reflect.TypeOf(item)),
+			}
+		}
+
+		var err error
+		if res, err = res.Assoc(vec.nth(0), vec.nth(1)); err != nil {
+			return nil, err
+		}
+	}
+
+	if res == nil {
+		res = &Map{}
+	}
+	return res, nil
+}
+
+// each calls f for every key-value pair in the map until f returns error.
+func (m *Map) each(f func(key, val Any) error) error {
+	if m.Size() == 0 {
+		return nil
+	}
+	return m.root.each(f)
+}
+
+func (n *hamtNode) each(f func(key, val Any) error) error {
+	for _, e := range n.entries {
+		if e.node != nil {
+			if err := e.node.each(f); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, kv := range e.kvs {
+			if err := f(kv.key, kv.val); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (n *hamtNode) assoc(shift uint, hash uint64, key, val Any) (*hamtNode, bool, error) {
+	bit := bitFor(hash, shift)
+	idx := n.index(bit)
+
+	if n.bitmap&bit == 0 {
+		leaf := hamtEntry{hash: hash, kvs: []mapEntry{{key: key, val: val}}}
+		entries := make([]hamtEntry, 0, len(n.entries)+1)
+		entries = append(entries, n.entries[:idx]...)
+		entries = append(entries, leaf)
+		entries = append(entries, n.entries[idx:]...)
+		return &hamtNode{bitmap: n.bitmap | bit, entries: entries}, true, nil
+	}
+
+	e := n.entries[idx]
+	var added bool
+	switch {
+	case e.node != nil:
+		child, childAdded, err := e.node.assoc(shift+hamtBits, hash, key, val)
+		if err != nil {
+			return nil, false, err
+		}
+		e, added = hamtEntry{node: child}, childAdded
+
+	case e.hash == hash:
+		i, err := e.find(key)
+		if err != nil {
+			return nil, false, err
+		}
+
+		kvs := append([]mapEntry(nil), e.kvs...)
+		if i < 0 {
+			kvs, added = append(kvs, mapEntry{key: key, val: val}), true
+		} else {
+			kvs[i].val = val
+		}
+		e = hamtEntry{hash: hash, kvs: kvs}
+
+	default:
+		leaf := hamtEntry{hash: hash, kvs: []mapEntry{{key: key, val: val}}}
+		e, added = hamtEntry{node: mergeLeaves(shift+hamtBits, e, leaf)}, true
+	}
+
+	return n.withEntry(idx, e), added, nil
+}
+
+func (n *hamtNode) dissoc(shift uint, hash uint64, key Any) (*hamtNode, bool, error) {
+	bit := bitFor(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false, nil
+	}
+
+	idx := n.index(bit)
+	e := n.entries[idx]
+
+	if e.node != nil {
+		child, removed, err := e.node.dissoc(shift+hamtBits, hash, key)
+		if err != nil || !removed {
+			return n, false, err
+		}
+
+		switch {
+		case len(child.entries) == 0:
+			return n.withoutEntry(idx, bit), true, nil
+		case len(child.entries) == 1 && child.entries[0].node == nil:
+			// pull the only remaining leaf up to keep the trie compact.
+			return n.withEntry(idx, child.entries[0]), true, nil
+		default:
+			return n.withEntry(idx, hamtEntry{node: child}), true, nil
+		}
+	}
+
+	if e.hash != hash {
+		return n, false, nil
+	}
+
+	i, err := e.find(key)
+	if err != nil || i < 0 {
+		return n, false, err
+	}
+
+	if len(e.kvs) == 1 {
+		return n.withoutEntry(idx, bit), true, nil
+	}
+
+	kvs := make([]mapEntry, 0, len(e.kvs)-1)
+	kvs = append(kvs, e.kvs[:i]...)
+	kvs = append(kvs, e.kvs[i+1:]...)
+	return n.withEntry(idx, hamtEntry{hash: hash, kvs: kvs}), true, nil
+}
+
+func (n *hamtNode) index(bit uint32) int { return bits.OnesCount32(n.bitmap & (bit - 1)) }
+
+func (n *hamtNode) withEntry(idx int, e hamtEntry) *hamtNode {
+	entries := append([]hamtEntry(nil), n.entries...)
+	entries[idx] = e
+	return &hamtNode{bitmap: n.bitmap, entries: entries}
+}
+
+func (n *hamtNode) withoutEntry(idx int, bit uint32) *hamtNode {
+	entries := make([]hamtEntry, 0, len(n.entries)-1)
+	entries = append(entries, n.entries[:idx]...)
+	entries = append(entries, n.entries[idx+1:]...)
+	return &hamtNode{bitmap: n.bitmap &^ bit, entries: entries}
+}
+
+// find returns the index of the key in the bucket or -1 if not found.
+func (e hamtEntry) find(key Any) (int, error) {
+	for i, kv := range e.kvs {
+		eq, err := Eq(kv.key, key)
+		if err != nil {
+			return -1, err
+		} else if eq {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// mergeLeaves returns a node containing both the leaves. Hashes of the leaves
+// must be different.
+func mergeLeaves(shift uint, a, b hamtEntry) *hamtNode {
+	bitA, bitB := bitFor(a.hash, shift), bitFor(b.hash, shift)
+	if bitA == bitB {
+		return &hamtNode{
+			bitmap:  bitA,
+			entries: []hamtEntry{{node: mergeLeaves(shift+hamtBits, a, b)}},
+		}
+	}
+
+	if bitA > bitB {
+		a, b = b, a
+	}
+	return &hamtNode{bitmap: bitA | bitB, entries: []hamtEntry{a, b}}
+}
+
+func bitFor(hash uint64, shift uint) uint32 { return 1 << ((hash >> shift) & hamtMask) }
+
+// mapSeq is a Seq view of the entries of a map from the position of cur in
+// the trie. Items are [key val] vectors, or the keys if keys is true (used by
+// Set). The trie is walked one entry at a time, so walking the entire seq is
+// linear in the size of the map.
+type mapSeq struct {
+	cur   *hamtCursor
+	count int
+	keys  bool
+}
+
+// hamtCursor points to a key-value pair in the trie. Cursors are immutable and
+// share the parents, which point to the sub-trie entries of the ancestors.
+type hamtCursor struct {
+	node   *hamtNode
+	entry  int
+	kv     int
+	parent *hamtCursor
+}
+
+func newMapSeq(m *Map, keys bool) *mapSeq {
+	seq := &mapSeq{count: m.Size(), keys: keys}
+	if seq.count > 0 {
+		seq.cur = leftmost(m.root, nil)
+	}
+	return seq
+}
+
+// leftmost returns the cursor pointing to the first key-value pair in the
+// sub-trie rooted at node.
+func leftmost(node *hamtNode, parent *hamtCursor) *hamtCursor {
+	for node.entries[0].node != nil {
+		parent = &hamtCursor{node: node, parent: parent}
+		node = node.entries[0].node
+	}
+	return &hamtCursor{node: node, parent: parent}
+}
+
+// next returns the cursor pointing to the key-value pair after c, or nil if c
+// points to the last one.
+func (c *hamtCursor) next() *hamtCursor {
+	if c.kv+1 < len(c.node.entries[c.entry].kvs) {
+		return &hamtCursor{node: c.node, entry: c.entry, kv: c.kv + 1, parent: c.parent}
+	}
+
+	for ; c != nil; c = c.parent {
+		if i := c.entry + 1; i < len(c.node.entries) {
+			if sub := c.node.entries[i].node; sub != nil {
+				return leftmost(sub, &hamtCursor{node: c.node, entry: i, parent: c.parent})
+			}
+			return &hamtCursor{node: c.node, entry: i, parent: c.parent}
+		}
+	}
+	return nil
+}
+
+func (ms *mapSeq) Count() (int, error) { return ms.count, nil }
+
+func (ms *mapSeq) First() (Any, error) {
+	if ms.cur == nil {
+		return nil, nil
+	}
+
+	kv := ms.cur.node.entries[ms.cur.entry].kvs[ms.cur.kv]
+	if ms.keys {
+		return kv.key, nil
+	}
+	return NewVector(kv.key, kv.val), nil
+}
+
+func (ms *mapSeq) Next() (Seq, error) {
+	if ms.cur == nil {
+		return nil, nil
+	}
+	return &mapSeq{cur: ms.cur.next(), count: ms.count - 1, keys: ms.keys}, nil
+}
+
+func (ms *mapSeq) Conj(items ...Any) (res Seq, err error) {
+	res = ms
+	for _, item := range items {
+		if res, err = Cons(item, res); err != nil {
+			break
+		}
+	}
+	return
+}
+
+func (ms *mapSeq) SExpr() (string, error) {
+	return SeqString(ms, "(", ")", " ")
+}
+
+// Equals returns true if the other value is a list or a seq and contains the
+// same values in the same order, same as LinkedList.
+func (ms *mapSeq) Equals(other Any) (bool, error) {
+	switch o := other.(type) {
+	case *mapSeq:
+		return seqEquals(ms, o)
+	case *vectorSeq:
+		return seqEquals(ms, o)
+	case *LinkedList:
+		return seqEquals(ms, o)
+	case *LazySeq:
+		return o.Equals(ms)
+	}
+	return false, nil
+}
+
+// Hash returns the same hash as a list with the same items.
+func (ms *mapSeq) Hash() (uint64, error) { return hashOrdered(hashTagList, ms) }
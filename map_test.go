@@ -0,0 +1,345 @@
+package parens_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spy16/parens"
+)
+
+func TestMap_Assoc(t *testing.T) {
+	t.Parallel()
+
+	const n = 5000
+
+	m := &parens.Map{}
+	for i := 0; i < n; i++ {
+		var err error
+		m, err = m.Assoc(parens.Int64(i), parens.String(fmt.Sprint(i)))
+		requireNoErr(t, err)
+	}
+	assertEqual(t, n, m.Size())
+
+	for i := 0; i < n; i++ {
+		v, found, err := m.Get(parens.Int64(i))
+		requireNoErr(t, err)
+		if !found || v != parens.String(fmt.Sprint(i)) {
+			t.Fatalf("Get(%d) = (%v, %t), want (\"%d\", true)", i, v, found, i)
+		}
+	}
+
+	_, found, err := m.Get(parens.Int64(n))
+	requireNoErr(t, err)
+	assertEqual(t, false, found)
+
+	// replacing an existing key must not change the count or the original.
+	updated, err := m.Assoc(parens.Int64(10), parens.Keyword("x"))
+	requireNoErr(t, err)
+	assertEqual(t, n, updated.Size())
+
+	v, _, err := m.Get(parens.Int64(10))
+	requireNoErr(t, err)
+	assertEqual(t, parens.String("10"), v)
+}
+
+func TestMap_Dissoc(t *testing.T) {
+	t.Parallel()
+
+	const n = 2000
+
+	m := &parens.Map{}
+	for i := 0; i < n; i++ {
+		var err error
+		m, err = m.Assoc(parens.Int64(i), parens.Int64(i))
+		requireNoErr(t, err)
+	}
+
+	orig := m
+	for i := 0; i < n; i += 2 {
+		var err error
+		m, err = m.Dissoc(parens.Int64(i))
+		requireNoErr(t, err)
+	}
+	assertEqual(t, n/2, m.Size())
+	assertEqual(t, n, orig.Size())
+
+	for i := 0; i < n; i++ {
+		found, err := m.HasKey(parens.Int64(i))
+		requireNoErr(t, err)
+		if found != (i%2 == 1) {
+			t.Fatalf("HasKey(%d) = %t", i, found)
+		}
+	}
+
+	same, err := m.Dissoc(parens.Int64(0))
+	requireNoErr(t, err)
+	if same != m {
+		t.Errorf("expected same map when dissociating missing key")
+	}
+}
+
+func TestMap_Collisions(t *testing.T) {
+	t.Parallel()
+
+	m, err := parens.NewMap(
+		collidingKey("a"), parens.Int64(1),
+		collidingKey("b"), parens.Int64(2),
+		collidingKey("c"), parens.Int64(3),
+	)
+	requireNoErr(t, err)
+	assertEqual(t, 3, m.Size())
+
+	v, found, err := m.Get(collidingKey("b"))
+	requireNoErr(t, err)
+	assertEqual(t, true, found)
+	assertEqual(t, parens.Int64(2), v)
+
+	m, err = m.Dissoc(collidingKey("b"))
+	requireNoErr(t, err)
+	assertEqual(t, 2, m.Size())
+
+	found, err = m.HasKey(collidingKey("b"))
+	requireNoErr(t, err)
+	assertEqual(t, false, found)
+
+	found, err = m.HasKey(collidingKey("c"))
+	requireNoErr(t, err)
+	assertEqual(t, true, found)
+}
+
+func TestMap_NotHashable(t *testing.T) {
+	t.Parallel()
+
+	_, err := parens.NewMap(struct{}{}, parens.Nil{})
+	if !errors.Is(err, parens.ErrNotHashable) {
+		t.Errorf("expected ErrNotHashable, got %#v", err)
+	}
+}
+
+func TestMap_Equals(t *testing.T) {
+	t.Parallel()
+
+	a, err := parens.NewMap(parens.Keyword("a"), parens.Int64(1), parens.Keyword("b"), parens.NewVector())
+	requireNoErr(t, err)
+
+	for _, tt := range []struct {
+		desc string
+		kvs  []parens.Any
+		want bool
+	}{
+		{
+			desc: "different insertion order",
+			kvs:  []parens.Any{parens.Keyword("b"), parens.NewVector(), parens.Keyword("a"), parens.Int64(1)},
+			want: true,
+		},
+		{
+			desc: "different value",
+			kvs:  []parens.Any{parens.Keyword("a"), parens.Int64(2), parens.Keyword("b"), parens.NewVector()},
+		},
+		{
+			desc: "missing key",
+			kvs:  []parens.Any{parens.Keyword("a"), parens.Int64(1)},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := parens.NewMap(tt.kvs...)
+			requireNoErr(t, err)
+
+			got, err := parens.Eq(a, b)
+			requireNoErr(t, err)
+			assertEqual(t, tt.want, got)
+		})
+	}
+}
+
+func TestMap_Seq(t *testing.T) {
+	t.Parallel()
+
+	m, err := parens.NewMap(parens.Keyword("a"), parens.Int64(1), parens.Keyword("b"), parens.Int64(2))
+	requireNoErr(t, err)
+
+	got := &parens.Map{}
+	err = parens.ForEach(m, func(item parens.Any) (bool, error) {
+		seq, err := got.Conj(item)
+		got = seq.(*parens.Map)
+		return err != nil, err
+	})
+	requireNoErr(t, err)
+
+	eq, err := parens.Eq(m, got)
+	requireNoErr(t, err)
+	assertEqual(t, true, eq)
+
+	_, err = m.Conj(parens.Int64(1))
+	if err == nil {
+		t.Errorf("expected error when adding non-entry value to map")
+	}
+}
+
+func TestMap_Seq_Walk(t *testing.T) {
+	t.Parallel()
+
+	m := &parens.Map{}
+	for i := 0; i < 1000; i++ {
+		var err error
+		m, err = m.Assoc(parens.Int64(i), parens.Int64(i*i))
+		requireNoErr(t, err)
+	}
+
+	for _, key := range []string{"a", "b", "c"} {
+		var err error
+		m, err = m.Assoc(collidingKey(key), parens.Keyword(key))
+		requireNoErr(t, err)
+	}
+
+	// removal leaves sub-tries with fewer entries.
+	for i := 0; i < 1000; i += 3 {
+		var err error
+		m, err = m.Dissoc(parens.Int64(i))
+		requireNoErr(t, err)
+	}
+
+	seen := map[parens.Any]bool{}
+	var seq parens.Seq = m
+	for n := m.Size(); n > 0; n-- {
+		cnt, err := seq.Count()
+		requireNoErr(t, err)
+		assertEqual(t, n, cnt)
+
+		entry, err := seq.First()
+		requireNoErr(t, err)
+
+		key, err := entry.(*parens.Vector).Nth(0)
+		requireNoErr(t, err)
+		if seen[key] {
+			t.Fatalf("key %v visited twice", key)
+		}
+		seen[key] = true
+
+		val, found, err := m.Get(key)
+		requireNoErr(t, err)
+		assertEqual(t, true, found)
+		assertEqual(t, parens.NewVector(key, val), entry)
+
+		seq, err = seq.Next()
+		requireNoErr(t, err)
+	}
+	assertEqual(t, m.Size(), len(seen))
+
+	// the seq after the last entry is empty.
+	v, err := seq.First()
+	requireNoErr(t, err)
+	assertEqual(t, nil, v)
+
+	eq, err := parens.Eq(seq, parens.NewList())
+	requireNoErr(t, err)
+	assertEqual(t, true, eq)
+}
+
+func TestMap_Seq_Equals(t *testing.T) {
+	t.Parallel()
+
+	m, err := parens.NewMap(parens.Keyword("a"), parens.Int64(1), parens.Keyword("b"), parens.Int64(2))
+	requireNoErr(t, err)
+
+	first, err := m.First()
+	requireNoErr(t, err)
+
+	rest, err := m.Next()
+	requireNoErr(t, err)
+
+	// rest holds the entry other than the first one, in either order.
+	entry := parens.NewVector(parens.Keyword("b"), parens.Int64(2))
+	if eq, _ := parens.Eq(first, entry); eq {
+		entry = parens.NewVector(parens.Keyword("a"), parens.Int64(1))
+	}
+	list := parens.NewList(entry)
+
+	// the seq is equal to and has the same hash as the list of the entries.
+	for _, pair := range [][2]parens.Any{{rest, list}, {list, rest}} {
+		eq, err := parens.Eq(pair[0], pair[1])
+		requireNoErr(t, err)
+		assertEqual(t, true, eq)
+	}
+
+	h1, err := parens.Hash(rest)
+	requireNoErr(t, err)
+	h2, err := parens.Hash(list)
+	requireNoErr(t, err)
+	assertEqual(t, h2, h1)
+
+	got, err := toString(rest)
+	requireNoErr(t, err)
+	want, err := toString(list)
+	requireNoErr(t, err)
+	assertEqual(t, want, got)
+}
+
+func TestMap_SExpr(t *testing.T) {
+	t.Parallel()
+
+	s, err := (&parens.Map{}).SExpr()
+	requireNoErr(t, err)
+	assertEqual(t, "{}", s)
+
+	m, err := parens.NewMap(parens.Keyword("a"), parens.NewVector(parens.Int64(1)))
+	requireNoErr(t, err)
+
+	s, err = m.SExpr()
+	requireNoErr(t, err)
+	assertEqual(t, "{:a [1]}", s)
+}
+
+func TestMap_Eval(t *testing.T) {
+	t.Parallel()
+
+	want, err := parens.NewMap(
+		parens.Keyword("a"), parens.Int64(1),
+		parens.String("b"), parens.NewVector(parens.Int64(1)),
+	)
+	requireNoErr(t, err)
+
+	got, err := evalString(parens.New(), `(def x 1) {:a x "b" [x]}`)
+	requireNoErr(t, err)
+
+	eq, err := parens.Eq(want, got)
+	requireNoErr(t, err)
+	if !eq {
+		t.Errorf("Eval() got = %v, want = %v", got, want)
+	}
+
+	got, err = evalString(parens.New(), "(def x 1) `{:a ~x}")
+	requireNoErr(t, err)
+
+	eq, err = parens.Eq(mustMap(t, parens.Keyword("a"), parens.Int64(1)), got)
+	requireNoErr(t, err)
+	if !eq {
+		t.Errorf("Eval() got = %v, want = {:a 1}", got)
+	}
+
+	_, err = evalString(parens.New(), `(def x :a) {x 1 :a 2}`)
+	if !errors.Is(err, parens.ErrDuplicateKey) {
+		t.Errorf("expected ErrDuplicateKey, got %#v", err)
+	}
+
+	_, err = evalString(parens.New(), `(def x [1 2]) {x 1 [1 2] 2}`)
+	if err == nil || !strings.Contains(err.Error(), "[1 2]") {
+		t.Errorf("expected duplicate key error with '[1 2]', got %v", err)
+	}
+}
+
+func mustMap(t *testing.T, kvs ...parens.Any) *parens.Map {
+	t.Helper()
+	m, err := parens.NewMap(kvs...)
+	requireNoErr(t, err)
+	return m
+}
+
+// collidingKey is a Hashable value whose hash is the same for all values.
+type collidingKey string
+
+func (ck collidingKey) Hash() (uint64, error) { return 42, nil }
+
+func (ck collidingKey) Equals(other parens.Any) (bool, error) { return ck == other, nil }
@@ -0,0 +1,155 @@
+package parens
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	_ WithMeta = Symbol("")
+	_ WithMeta = MetaSymbol{}
+	_ WithMeta = (*LinkedList)(nil)
+	_ WithMeta = (*Vector)(nil)
+	_ WithMeta = (*Map)(nil)
+	_ WithMeta = (*Set)(nil)
+	_ WithMeta = (*Fn)(nil)
+)
+
+// IMeta is implemented by values that can carry metadata. Metadata is a map
+// of arbitrary values (e.g., {:doc "..."}) that annotates the value but does
+// not affect its equality or hash.
+type IMeta interface {
+	// Meta returns the metadata of the value, nil if it has none.
+	Meta() *Map
+}
+
+// WithMeta is implemented by values that can return a copy of themselves with
+// different metadata. The original value is not modified.
+type WithMeta interface {
+	IMeta
+	WithMeta(meta *Map) Any
+}
+
+// Meta returns the metadata of the value. Returns nil if the value does not
+// implement IMeta or has no metadata.
+func Meta(v Any) *Map {
+	if m, ok := v.(IMeta); ok {
+		return m.Meta()
+	}
+	return nil
+}
+
+// MergeMeta returns the value with the key-value pairs of meta added to its
+// existing metadata. Returns error with ErrMetaNotSupported cause if the value
+// does not implement WithMeta.
+func MergeMeta(v Any, meta *Map) (Any, error) {
+	wm, err := toWithMeta("with-meta", v)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := wm.Meta()
+	if merged == nil {
+		merged = &Map{}
+	}
+
+	err = meta.each(func(key, val Any) error {
+		merged, err = merged.Assoc(key, val)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wm.WithMeta(merged), nil
+}
+
+// metaFn implements (meta x). Returns the metadata of x or nil.
+func metaFn(_ *Env, args ...Any) (Any, error) {
+	if err := checkArity("meta", args, 1); err != nil {
+		return nil, err
+	}
+
+	if m := Meta(args[0]); m != nil {
+		return m, nil
+	}
+	return Nil{}, nil
+}
+
+// withMetaFn implements (with-meta x m). Returns x with its metadata replaced
+// by the map m. Passing nil removes the metadata.
+func withMetaFn(_ *Env, args ...Any) (Any, error) {
+	if err := checkArity("with-meta", args, 2); err != nil {
+		return nil, err
+	}
+
+	wm, err := toWithMeta("with-meta", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := toMetaMap("with-meta", args[1])
+	if err != nil {
+		return nil, err
+	}
+	return wm.WithMeta(meta), nil
+}
+
+// varyMetaFn implements (vary-meta x f & args). Returns x with its metadata
+// replaced by the result of (f (meta x) args...).
+func varyMetaFn(env *Env, args ...Any) (Any, error) {
+	if len(args) < 2 {
+		return nil, Error{
+			Cause:   ErrArity,
+			Message: fmt.Sprintf("vary-meta requires at least 2 arguments, got %d", len(args)),
+		}
+	}
+
+	wm, err := toWithMeta("vary-meta", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var cur Any = Nil{}
+	if m := wm.Meta(); m != nil {
+		cur = m
+	}
+
+	res, err := invoke(env, args[1], append([]Any{cur}, args[2:]...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := toMetaMap("vary-meta", res)
+	if err != nil {
+		return nil, err
+	}
+	return wm.WithMeta(meta), nil
+}
+
+func toWithMeta(name string, v Any) (WithMeta, error) {
+	wm, ok := v.(WithMeta)
+	if !ok {
+		return nil, Error{
+			Cause:   ErrMetaNotSupported,
+			Message: fmt.Sprintf("%s: value of type '%s'", name, reflect.TypeOf(v)),
+		}
+	}
+	return wm, nil
+}
+
+// toMetaMap returns the metadata map. Nil is treated as no metadata.
+func toMetaMap(name string, v Any) (*Map, error) {
+	if IsNil(v) {
+		return nil, nil
+	}
+
+	m, ok := v.(*Map)
+	if !ok {
+		return nil, Error{
+			Cause:   errors.New("invalid metadata"),
+			Message: fmt.Sprintf("%s: metadata must be a map, not '%s'", name, reflect.TypeOf(v)),
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,133 @@
+package parens_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spy16/parens"
+)
+
+func TestMetaFns(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    string
+		wantErr error
+	}{
+		{title: "ReaderMeta", src: `(meta ^:a [1])`, want: "{:a true}"},
+		{title: "ReaderMetaMap", src: `(meta ^{:doc "v"} {:k 1})`, want: `{:doc "v"}`},
+		{title: "ReaderMetaSet", src: `(meta ^:a #{1})`, want: "{:a true}"},
+		{title: "ReaderMetaQuoted", src: `(meta '^:a (foo))`, want: "{:a true}"},
+		{title: "NoMeta", src: `(meta [1])`, want: "nil"},
+		{title: "NotIMeta", src: `(meta 1)`, want: "nil"},
+		{title: "MetaArity", src: `(meta)`, wantErr: parens.ErrArity},
+		{title: "WithMeta", src: `(meta (with-meta [1 2] {:doc "v"}))`, want: `{:doc "v"}`},
+		{title: "WithMetaValue", src: `(with-meta [1 2] {:doc "v"})`, want: "[1 2]"},
+		{title: "WithMetaReplaces", src: `(meta (with-meta ^:a [] {:b 1}))`, want: "{:b 1}"},
+		{title: "WithMetaNil", src: `(meta (with-meta ^:a [1] nil))`, want: "nil"},
+		{title: "WithMetaList", src: `(meta (with-meta '(1) {:a 1}))`, want: "{:a 1}"},
+		{title: "WithMetaEmptyList", src: `(meta (with-meta '() {:a 1}))`, want: "{:a 1}"},
+		{title: "WithMetaMap", src: `(meta (with-meta {} {:a 1}))`, want: "{:a 1}"},
+		{title: "WithMetaSet", src: `(meta (with-meta #{} {:a 1}))`, want: "{:a 1}"},
+		{title: "WithMetaSymbol", src: `(meta (with-meta 'foo {:a 1}))`, want: "{:a 1}"},
+		{title: "ReaderMetaSymbol", src: `(meta '^:a foo)`, want: "{:a true}"},
+		{title: "MetaSymbolResolves", src: `(def x 1) ^:a x`, want: "1"},
+		{title: "MetaSymbolLet", src: `(let [^:a x 1] x)`, want: "1"},
+		{title: "MetaSymbolParams", src: `((fn ^:a f [^:a x & ^:b xs] xs) 1 2)`, want: "(2)"},
+		{title: "WithMetaFn", src: `(meta (with-meta (fn [] 1) {:a 1}))`, want: "{:a 1}"},
+		{title: "WithMetaFnInvokable", src: `((with-meta (fn [x] x) {:a 1}) 10)`, want: "10"},
+		{title: "WithMetaNotSupported", src: `(with-meta 1 {})`, wantErr: parens.ErrMetaNotSupported},
+		{title: "WithMetaNotMap", src: `(with-meta [] [:a])`, wantErr: errors.New("invalid metadata")},
+		{title: "WithMetaArity", src: `(with-meta [])`, wantErr: parens.ErrArity},
+		{title: "VaryMeta", src: `(meta (vary-meta ^:a [] (fn [m] {:m m})))`, want: "{:m {:a true}}"},
+		{title: "VaryMetaArgs", src: `(meta (vary-meta [] (fn [m k v] {k v}) :b 2))`, want: "{:b 2}"},
+		{title: "VaryMetaNil", src: `(meta (vary-meta ^:a [] (fn [m] nil)))`, want: "nil"},
+		{title: "VaryMetaNotInvokable", src: `(vary-meta [] 1)`, wantErr: parens.ErrNotInvokable},
+		{title: "VaryMetaArity", src: `(vary-meta [])`, wantErr: parens.ErrArity},
+		{title: "FnMeta", src: `(meta ^{:doc "x"} (fn [] 1))`, want: `{:doc "x"}`},
+		{title: "FnMetaInvokable", src: `(^:a (fn [x] x) 10)`, want: "10"},
+		{title: "DefMergesFnMeta", src: `(def ^:b f ^:a (fn [] 1)) (count (meta f))`, want: "2"},
+		{title: "DefFn", src: `(def ^{:doc "id"} f (fn [x] x)) (meta f)`, want: `{:doc "id"}`},
+		{title: "DefMerges", src: `(def ^:b f (with-meta (fn [] 1) {:a 1})) (count (meta f))`, want: "2"},
+		{title: "DefNotIMeta", src: `(def ^:private x 1) x`, want: "1"},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			env := parens.New(parens.WithGlobals(map[string]parens.Any{
+				"nil": parens.Nil{},
+			}, nil))
+
+			got, err := evalString(env, tt.src)
+			if tt.wantErr != nil {
+				if err == nil || !(errors.Is(err, tt.wantErr) || strings.Contains(err.Error(), tt.wantErr.Error())) {
+					t.Fatalf("expected error %v, got %#v", tt.wantErr, err)
+				}
+				return
+			}
+			requireNoErr(t, err)
+
+			s, err := parens.SeqString(parens.NewList(got), "", "", "")
+			requireNoErr(t, err)
+			assertEqual(t, tt.want, s)
+		})
+	}
+}
+
+func TestMeta_Equality(t *testing.T) {
+	t.Parallel()
+
+	meta, err := parens.NewMap(parens.Keyword("a"), parens.Int64(1))
+	requireNoErr(t, err)
+
+	for _, v := range []parens.WithMeta{
+		parens.Symbol("foo"),
+		parens.NewList(parens.Int64(1)).(*parens.LinkedList),
+		parens.NewVector(parens.Int64(1)),
+		meta,
+	} {
+		withMeta := v.WithMeta(meta)
+		assertEqual(t, meta, parens.Meta(withMeta))
+
+		eq, err := parens.Eq(v, withMeta)
+		requireNoErr(t, err)
+		assertEqual(t, true, eq)
+
+		h1, err := parens.Hash(v)
+		requireNoErr(t, err)
+
+		h2, err := parens.Hash(withMeta)
+		requireNoErr(t, err)
+		assertEqual(t, h1, h2)
+	}
+}
+
+func TestMeta_Retained(t *testing.T) {
+	t.Parallel()
+
+	meta, err := parens.NewMap(parens.Keyword("a"), parens.Int64(1))
+	requireNoErr(t, err)
+
+	orig := parens.NewVector(parens.Int64(1))
+	vec := orig.WithMeta(meta).(*parens.Vector)
+	if parens.Meta(orig) != nil {
+		t.Errorf("expected WithMeta to not modify the original vector")
+	}
+
+	res, err := vec.Conj(parens.Int64(2))
+	requireNoErr(t, err)
+	assertEqual(t, meta, parens.Meta(res))
+
+	m := (&parens.Map{}).WithMeta(meta).(*parens.Map)
+	m, err = m.Assoc(parens.Keyword("k"), parens.Int64(1))
+	requireNoErr(t, err)
+	assertEqual(t, meta, parens.Meta(m))
+
+	set := (&parens.Set{}).WithMeta(meta).(*parens.Set)
+	res, err = set.Conj(parens.Int64(1))
+	requireNoErr(t, err)
+	assertEqual(t, meta, parens.Meta(res))
+}
@@ -0,0 +1,287 @@
+package parens
+
+import (
+	"math"
+	"math/big"
+)
+
+var (
+	_ Any = BigInt{}
+	_ Any = Ratio{}
+
+	_ Number = Int64(0)
+	_ Number = BigInt{}
+	_ Number = Ratio{}
+	_ Number = Float64(0)
+)
+
+// Number is implemented by the builtin numeric types. Numbers form a tower:
+//
+//	Int64 → BigInt → Ratio → Float64
+//
+// When two numbers of different types are combined, the one lower in the
+// tower is converted to the type of the other (contagion). Numbers compare by
+// their exact values irrespective of their types. For example, (= 1 1.0) is
+// true, but 2^53+1 is not equal to the Float64 nearest to it. Equal numbers
+// have the same hash irrespective of their types.
+//
+// Number is closed: its unexported method restricts the implementations to the
+// builtin types, since promotion needs to convert between all the types in the
+// tower. Host numeric types can implement Comparable, EqualityProvider and
+// Hashable instead, but do not take part in the promotion.
+type Number interface {
+	Comparable
+	EqualityProvider
+	Hashable
+
+	level() numLevel
+}
+
+// numLevel is the position of a numeric type in the tower.
+type numLevel int
+
+const (
+	levelInt64 numLevel = iota
+	levelBigInt
+	levelRatio
+	levelFloat64
+)
+
+// BigInt represents an arbitrary-precision integer Value. The wrapped value
+// is never modified.
+type BigInt struct{ val *big.Int }
+
+// NewBigInt returns a BigInt with the value of v. v is copied.
+func NewBigInt(v *big.Int) BigInt { return BigInt{val: new(big.Int).Set(v)} }
+
+// Big returns a copy of the value as *big.Int.
+func (bi BigInt) Big() *big.Int { return new(big.Int).Set(bi.int()) }
+
+// SExpr returns a valid s-expression representing BigInt.
+func (bi BigInt) SExpr() (string, error) { return bi.String(), nil }
+
+// Equals returns true if 'other' is a number with the same Value.
+func (bi BigInt) Equals(other Any) (bool, error) { return numEq(bi, other), nil }
+
+// Comp performs comparison against another number.
+func (bi BigInt) Comp(other Any) (int, error) { return numComp(bi, other) }
+
+// Hash returns the hash of the number.
+func (bi BigInt) Hash() (uint64, error) { return hashNumber(bi), nil }
+
+func (bi BigInt) String() string { return bi.int().String() + "N" }
+
+func (bi BigInt) level() numLevel { return levelBigInt }
+
+func (bi BigInt) int() *big.Int {
+	if bi.val == nil {
+		return new(big.Int)
+	}
+	return bi.val
+}
+
+// Ratio represents an exact rational number Value.  The wrapped value is never
+// modified.
+type Ratio struct{ val *big.Rat }
+
+// NewRatio returns a Ratio with the value of v. v is copied.
+func NewRatio(v *big.Rat) Ratio { return Ratio{val: new(big.Rat).Set(v)} }
+
+// Rat returns a copy of the value as *big.Rat.
+func (r Ratio) Rat() *big.Rat { return new(big.Rat).Set(r.rat()) }
+
+// SExpr returns a valid s-expression representing Ratio.
+func (r Ratio) SExpr() (string, error) { return r.String(), nil }
+
+// Equals returns true if 'other' is a number with the same Value.
+func (r Ratio) Equals(other Any) (bool, error) { return numEq(r, other), nil }
+
+// Comp performs comparison against another number.
+func (r Ratio) Comp(other Any) (int, error) { return numComp(r, other) }
+
+// Hash returns the hash of the number.
+func (r Ratio) Hash() (uint64, error) { return hashNumber(r), nil }
+
+func (r Ratio) String() string { return r.rat().String() }
+
+func (r Ratio) level() numLevel { return levelRatio }
+
+func (r Ratio) rat() *big.Rat {
+	if r.val == nil {
+		return new(big.Rat)
+	}
+	return r.val
+}
+
+// Promote converts both the numbers to the type of the one higher in the
+// numeric tower. Arithmetic on numbers of different types is performed on the
+// promoted values.
+func Promote(a, b Number) (Number, Number) {
+	la, lb := a.level(), b.level()
+	if la < lb {
+		return toLevel(a, lb), b
+	} else if lb < la {
+		return a, toLevel(b, la)
+	}
+	return a, b
+}
+
+// toLevel converts the number to the type at level l. l must not be lower
+// than the level of n.
+func toLevel(n Number, l numLevel) Number {
+	switch l {
+	case levelBigInt:
+		return BigInt{val: toBigInt(n)}
+	case levelRatio:
+		return Ratio{val: toRat(n)}
+	case levelFloat64:
+		return Float64(toFloat(n))
+	}
+	return n
+}
+
+func toBigInt(n Number) *big.Int {
+	switch v := n.(type) {
+	case Int64:
+		return big.NewInt(int64(v))
+	case BigInt:
+		return v.int()
+	}
+	panic("toBigInt: not an integer")
+}
+
+func toRat(n Number) *big.Rat {
+	switch v := n.(type) {
+	case Ratio:
+		return v.rat()
+	case Float64:
+		// exact value of the float. Must be finite.
+		return new(big.Rat).SetFloat64(float64(v))
+	}
+	return new(big.Rat).SetInt(toBigInt(n))
+}
+
+func toFloat(n Number) float64 {
+	switch v := n.(type) {
+	case Int64:
+		return float64(v)
+	case BigInt:
+		f, _ := new(big.Float).SetInt(v.int()).Float64()
+		return f
+	case Ratio:
+		f, _ := v.rat().Float64()
+		return f
+	case Float64:
+		return float64(v)
+	}
+	panic("toFloat: unknown number type")
+}
+
+// compareNumbers compares the numbers after promoting them to the same type.
+// NaN is neither less than nor greater than any number and compares as 0.
+// Use unordered to tell it apart from equality.
+func compareNumbers(a, b Number) int {
+	_, floatA := a.(Float64)
+	if _, floatB := b.(Float64); floatA != floatB {
+		return compareFloat(a, b)
+	}
+
+	a, b = Promote(a, b)
+	switch x := a.(type) {
+	case Int64:
+		if y := b.(Int64); x != y {
+			return sign(x > y)
+		}
+		return 0
+	case BigInt:
+		return x.int().Cmp(b.(BigInt).int())
+	case Ratio:
+		return x.rat().Cmp(b.(Ratio).rat())
+	}
+
+	x, y := a.(Float64), b.(Float64)
+	if x > y || x < y {
+		return sign(x > y)
+	}
+	return 0
+}
+
+// compareFloat compares a Float64 with an exact number by their exact values,
+// since promoting the exact number to Float64 loses precision (e.g., integers
+// above 2^53).
+func compareFloat(a, b Number) int {
+	if _, ok := a.(Float64); ok {
+		return -compareFloat(b, a)
+	}
+
+	f := float64(b.(Float64))
+	if math.IsNaN(f) {
+		return 0
+	} else if math.IsInf(f, 0) {
+		return sign(f < 0)
+	}
+	return toRat(a).Cmp(toRat(b))
+}
+
+// unordered returns true if either of the values is NaN, which is neither
+// less than, equal to nor greater than any number.
+func unordered(a, b Any) bool {
+	isNaN := func(v Any) bool {
+		f, ok := v.(Float64)
+		return ok && math.IsNaN(float64(f))
+	}
+	return isNaN(a) || isNaN(b)
+}
+
+func sign(positive bool) int {
+	if positive {
+		return 1
+	}
+	return -1
+}
+
+func numComp(n Number, other Any) (int, error) {
+	o, ok := other.(Number)
+	if !ok {
+		return 0, ErrIncomparableTypes
+	}
+	return compareNumbers(n, o), nil
+}
+
+func numEq(n Number, other Any) bool {
+	o, ok := other.(Number)
+	if !ok {
+		return false
+	}
+
+	// NaN is not equal to anything.
+	return !unordered(n, o) && compareNumbers(n, o) == 0
+}
+
+// maxExactInt is the largest integer such that all the integers with smaller
+// magnitude are exactly representable as float64.
+const maxExactInt = 1 << 53
+
+// hashNumber returns the hash of the number. Numbers exactly representable
+// as float64 are hashed by their float64 value so that equal numbers of any
+// type have the same hash. Other numbers cannot be equal to a Float64 and are
+// hashed by their exact value.
+func hashNumber(n Number) uint64 {
+	var f float64
+	if v, ok := n.(Float64); ok {
+		f = float64(v)
+	} else if v, ok := n.(Int64); ok && -maxExactInt <= v && v <= maxExactInt {
+		f = float64(v)
+	} else {
+		r := toRat(n)
+		exact := false
+		if f, exact = r.Float64(); !exact {
+			return hashString(hashTagNumber, r.RatString())
+		}
+	}
+
+	if f == 0 {
+		f = 0 // positive and negative zero are equal.
+	}
+	return hashUint64(hashTagNumber, math.Float64bits(f))
+}
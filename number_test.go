@@ -0,0 +1,138 @@
+package parens_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/spy16/parens"
+)
+
+func TestBigInt_Comp(t *testing.T) {
+	t.Parallel()
+
+	huge := parens.NewBigInt(new(big.Int).Lsh(big.NewInt(1), 100))
+	ten := parens.NewBigInt(big.NewInt(10))
+	third := parens.NewRatio(big.NewRat(1, 3))
+
+	for _, tt := range []struct {
+		desc    string
+		a, b    parens.Any
+		op      func(a, b parens.Any) (bool, error)
+		want    bool
+		wantErr bool
+	}{
+		{desc: "10N == 10", a: ten, b: parens.Int64(10), op: parens.Eq, want: true},
+		{desc: "10 == 10N", a: parens.Int64(10), b: ten, op: parens.Eq, want: true},
+		{desc: "10N == 10N", a: ten, b: parens.NewBigInt(big.NewInt(10)), op: parens.Eq, want: true},
+		{desc: "10N == 20/2", a: ten, b: parens.NewRatio(big.NewRat(20, 2)), op: parens.Eq, want: true},
+		{desc: "10N == 11", a: ten, b: parens.Int64(11), op: parens.Eq},
+		{desc: "2^100 > 10", a: huge, b: parens.Int64(10), op: parens.Gt, want: true},
+		{desc: "10 < 2^100", a: parens.Int64(10), b: huge, op: parens.Lt, want: true},
+		{desc: "1/3 < 1", a: third, b: parens.Int64(1), op: parens.Lt, want: true},
+		{desc: "0 < 1/3", a: parens.Int64(0), b: third, op: parens.Lt, want: true},
+		{desc: "1/3 <= 1/3", a: third, b: parens.NewRatio(big.NewRat(2, 6)), op: parens.Le, want: true},
+		{desc: "1/3 > 10N", a: third, b: ten, op: parens.Gt},
+		{desc: "10N < :a", a: ten, b: parens.Keyword("a"), op: parens.Lt, wantErr: true},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.op(tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			} else if tt.wantErr && !errors.Is(err, parens.ErrIncomparableTypes) {
+				t.Fatalf("expected ErrIncomparableTypes, got %#v", err)
+			}
+			assertEqual(t, tt.want, got)
+		})
+	}
+}
+
+func TestBigInt_Hash(t *testing.T) {
+	t.Parallel()
+
+	m, err := parens.NewMap(
+		parens.Int64(1), parens.Keyword("one"),
+		parens.NewRatio(big.NewRat(1, 2)), parens.Keyword("half"),
+	)
+	requireNoErr(t, err)
+
+	for desc, tt := range map[string]struct {
+		key  parens.Any
+		want parens.Any
+	}{
+		"BigInt":      {key: parens.NewBigInt(big.NewInt(1)), want: parens.Keyword("one")},
+		"Int Ratio":   {key: parens.NewRatio(big.NewRat(3, 3)), want: parens.Keyword("one")},
+		"Equal Ratio": {key: parens.NewRatio(big.NewRat(2, 4)), want: parens.Keyword("half")},
+	} {
+		v, found, err := m.Get(tt.key)
+		requireNoErr(t, err)
+		if !found || v != tt.want {
+			t.Errorf("%s: Get() = (%v, %t), want (%v, true)", desc, v, found, tt.want)
+		}
+	}
+}
+
+func TestNumber_ExactAboveFloatPrecision(t *testing.T) {
+	t.Parallel()
+
+	const limit = 1 << 53
+	float := parens.Float64(limit)
+	above := parens.Int64(limit + 1) // float64(limit+1) rounds to limit.
+	bigAbove := parens.NewBigInt(big.NewInt(limit + 1))
+
+	for _, tt := range []struct {
+		desc string
+		a, b parens.Any
+		op   func(a, b parens.Any) (bool, error)
+		want bool
+	}{
+		{desc: "2^53 == 2^53 float", a: parens.Int64(limit), b: float, op: parens.Eq, want: true},
+		{desc: "2^53+1 != 2^53 float", a: above, b: float, op: parens.Eq},
+		{desc: "2^53 float != 2^53+1", a: float, b: above, op: parens.Eq},
+		{desc: "2^53+1N != 2^53 float", a: bigAbove, b: float, op: parens.Eq},
+		{desc: "2^53+1 > 2^53 float", a: above, b: float, op: parens.Gt, want: true},
+		{desc: "2^53 float < 2^53+1N", a: float, b: bigAbove, op: parens.Lt, want: true},
+		{desc: "max int64 != 2^63 float", a: parens.Int64(math.MaxInt64), b: parens.Float64(1 << 63), op: parens.Eq},
+		{desc: "1/3 != 1/3 float", a: parens.NewRatio(big.NewRat(1, 3)), b: parens.Float64(1.0 / 3), op: parens.Eq},
+		{desc: "1/2 == 0.5", a: parens.NewRatio(big.NewRat(1, 2)), b: parens.Float64(0.5), op: parens.Eq, want: true},
+		{desc: "2^53+1 < +Inf", a: above, b: parens.Float64(math.Inf(1)), op: parens.Lt, want: true},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.op(tt.a, tt.b)
+			requireNoErr(t, err)
+			assertEqual(t, tt.want, got)
+		})
+	}
+
+	m, err := parens.NewMap(above, parens.Keyword("above"), float, parens.Keyword("float"))
+	requireNoErr(t, err)
+
+	for desc, tt := range map[string]struct {
+		key  parens.Any
+		want parens.Any
+	}{
+		"Int64 Above":  {key: above, want: parens.Keyword("above")},
+		"BigInt Above": {key: bigAbove, want: parens.Keyword("above")},
+		"Float":        {key: float, want: parens.Keyword("float")},
+		"Int64 Equal":  {key: parens.Int64(limit), want: parens.Keyword("float")},
+	} {
+		v, found, err := m.Get(tt.key)
+		requireNoErr(t, err)
+		if !found || v != tt.want {
+			t.Errorf("%s: Get() = (%v, %t), want (%v, true)", desc, v, found, tt.want)
+		}
+	}
+}
+
+func TestBigInt_SExpr(t *testing.T) {
+	t.Parallel()
+
+	s, err := parens.NewBigInt(big.NewInt(-42)).SExpr()
+	requireNoErr(t, err)
+	assertEqual(t, "-42N", s)
+
+	s, err = parens.NewRatio(big.NewRat(22, 7)).SExpr()
+	requireNoErr(t, err)
+	assertEqual(t, "22/7", s)
+}
@@ -1,5 +1,7 @@
 package parens
 
+import "context"
+
 // Option can be used with New() to customize initialization of Evaluator
 // Instance.
 type Option func(env *Env)
@@ -20,7 +22,8 @@ func WithGlobals(globals map[string]Any, factory func() ConcurrentMap) Option {
 	}
 }
 
-// WithMaxDepth sets the max depth allowed for stack.  Panics if depth == 0.
+// WithMaxDepth sets the max depth allowed for stack.  Invocations beyond the
+// limit fail with ErrMaxDepthExceeded.  Panics if depth == 0.
 func WithMaxDepth(depth uint) Option {
 	if depth == 0 {
 		panic("maxdepth must be nonzero.")
@@ -30,6 +33,18 @@ func WithMaxDepth(depth uint) Option {
 	}
 }
 
+// WithContext sets the context used for evaluation. Evaluation is aborted once
+// the context is cancelled or expires. Forked envs inherit the context. If nil,
+// context.Background() will be used.
+func WithContext(ctx context.Context) Option {
+	return func(env *Env) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		env.state.ctx = ctx
+	}
+}
+
 // WithExpander sets the macro Expander to be used by the p. If nil, a builtin
 // Expander will be used.
 func WithExpander(expander Expander) Option {
@@ -49,11 +64,16 @@ func WithAnalyzer(analyzer Analyzer) Option {
 		if analyzer == nil {
 			analyzer = &BuiltinAnalyzer{
 				SpecialForms: map[string]ParseSpecial{
-					"go":    parseGoExpr,
-					"do":    parseDoExpr,
-					"if":    parseIfExpr,
-					"def":   parseDefExpr,
-					"quote": parseQuoteExpr,
+					"go":           parseGoExpr,
+					"do":           parseDoExpr,
+					"if":           parseIfExpr,
+					"def":          parseDefExpr,
+					"quote":        parseQuoteExpr,
+					"fn":           parseFnExpr,
+					"let":          parseLetExpr,
+					"defmacro":     parseDefMacroExpr,
+					"syntax-quote": parseSyntaxQuoteExpr,
+					"lazy-seq":     parseLazySeqExpr,
 				},
 			}
 		}
@@ -66,5 +86,6 @@ func withDefaults(opts []Option) []Option {
 		WithAnalyzer(nil),
 		WithExpander(nil),
 		WithMaxDepth(10000),
+		WithGlobals(builtinGlobals(), nil),
 	}, opts...)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 )
 
 var (
@@ -16,15 +17,51 @@ var (
 	// ErrNotInvokable is returned by InvokeExpr when the target is not invokable.
 	ErrNotInvokable = errors.New("not invokable")
 
+	// ErrArity is returned when a function is invoked with a number of arguments
+	// not supported by any of its arities.
+	ErrArity = errors.New("wrong number of args")
+
+	// ErrMaxDepthExceeded is returned when the number of nested invocations
+	// exceeds the limit set using WithMaxDepth().
+	ErrMaxDepthExceeded = errors.New("max depth exceeded")
+
+	// ErrIndexOutOfBounds is returned when an index is not within the bounds
+	// of a collection.
+	ErrIndexOutOfBounds = errors.New("index out of bounds")
+
+	// ErrNotHashable is returned when a value that does not implement Hashable
+	// is used as a key of a Map.
+	ErrNotHashable = errors.New("not hashable")
+
+	// ErrDuplicateKey is returned when a map literal contains the same key more
+	// than once.
+	ErrDuplicateKey = errors.New("duplicate key")
+
 	// ErrIncomparableTypes is returned by Any.Comp when a comparison between two tpyes
 	// is undefined.  Users should generally consider the types to be not equal in such
 	// cases, but not assume any ordering.
 	ErrIncomparableTypes = errors.New("incomparable types")
+
+	// ErrNotSeq is returned when a sequence function is invoked with a value
+	// that is not a Seq.
+	ErrNotSeq = errors.New("not a seq")
+
+	// ErrInfiniteSeq is returned when an operation that requires realizing
+	// the entire seq (e.g., Count) is performed on an infinite seq.
+	ErrInfiniteSeq = errors.New("infinite seq")
+
+	// ErrRealizing is returned when a LazySeq is accessed by the evaluation
+	// realizing it (e.g., a lazy-seq whose body refers to the seq itself).
+	ErrRealizing = errors.New("seq is being realized")
+
+	// ErrMetaNotSupported is returned when attaching metadata to a value that
+	// does not implement WithMeta.
+	ErrMetaNotSupported = errors.New("metadata not supported")
 )
 
 // New returns a new root context initialised based on given options.
 func New(opts ...Option) *Env {
-	env := &Env{ctx: context.Background(), globals: newMutexMap()}
+	env := &Env{state: &evalState{ctx: context.Background()}, globals: newMutexMap()}
 	for _, opt := range withDefaults(opts) {
 		opt(env)
 	}
@@ -39,6 +76,32 @@ type SExpressable interface {
 	SExpr() (string, error)
 }
 
+// Position represents the positional information about a form in source.
+// Zero value represents an unknown position.
+type Position struct {
+	File string
+	Ln   int
+	Col  int
+}
+
+func (p Position) String() string {
+	if p.File == "" {
+		p.File = "<unknown>"
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Ln, p.Col)
+}
+
+// Span represents the region of source from which a form was read.
+type Span struct {
+	Begin, End Position
+}
+
+// Positional forms carry the span of source they were read from. Positional
+// forms are used to annotate analysis and evaluation errors.
+type Positional interface {
+	Span() Span
+}
+
 // Seq represents a sequence of values.
 type Seq interface {
 	Any
@@ -76,10 +139,16 @@ type Expr interface {
 }
 
 // Error is returned by all parens operations. Cause indicates the underlying
-// error type. Use errors.Is() with Cause to check for specific errors.
+// error type. Use errors.Is() with Cause to check for specific errors. Errors
+// returned by invocations carry the call stack at the point of failure which
+// can be obtained using StackTrace() or rendered using the "%+v" verb. Pos is
+// the source position of the failing form, if known.
 type Error struct {
 	Message string
 	Cause   error
+	Pos     Position
+
+	trace []StackFrame
 }
 
 // Is returns true if the other error is same as the cause of this error.
@@ -88,9 +157,45 @@ func (e Error) Is(other error) bool { return errors.Is(e.Cause, other) }
 // Unwrap returns the underlying cause of the error.
 func (e Error) Unwrap() error { return e.Cause }
 
+// StackTrace returns the call stack captured at the point of failure, with
+// the most recent call first. Returns nil if no trace was captured.
+func (e Error) StackTrace() []StackFrame { return e.trace }
+
 func (e Error) Error() string {
+	msg := e.Message
 	if e.Cause != nil {
-		return fmt.Sprintf("%v: %s", e.Cause, e.Message)
+		if e.Message == "" {
+			msg = e.Cause.Error()
+		} else {
+			msg = fmt.Sprintf("%v: %s", e.Cause, e.Message)
+		}
+	}
+
+	if e.Pos.Ln > 0 {
+		return fmt.Sprintf("%s: %s", e.Pos, msg)
+	}
+	return msg
+}
+
+// Format implements fmt.Formatter. The "%+v" verb renders the error followed
+// by the stack trace, one frame per line. All other verbs render the error
+// message only.
+func (e Error) Format(s fmt.State, verb rune) {
+	const maxFrames = 25
+
+	_, _ = io.WriteString(s, e.Error())
+	if verb != 'v' || !s.Flag('+') {
+		return
+	}
+
+	for i, frame := range e.trace {
+		if i == maxFrames {
+			_, _ = fmt.Fprintf(s, "\n  ... %d more", len(e.trace)-maxFrames)
+			break
+		}
+		_, _ = fmt.Fprintf(s, "\n  at %s", frame)
+		if frame.Pos.Ln > 0 {
+			_, _ = fmt.Fprintf(s, " [%s]", frame.Pos)
+		}
 	}
-	return e.Message
 }
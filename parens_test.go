@@ -1,6 +1,8 @@
 package parens_test
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/spy16/parens"
@@ -16,3 +18,149 @@ func assertNotNil(t *testing.T, v interface{}) {
 		t.Errorf("wanted non-nil value, got nil")
 	}
 }
+
+func TestError_StackTrace(t *testing.T) {
+	t.Parallel()
+
+	env := parens.New(parens.WithGlobals(map[string]parens.Any{
+		"fail": parens.GoFunc(func(_ *parens.Env, _ ...parens.Any) (parens.Any, error) {
+			return nil, errors.New("failed")
+		}),
+	}, nil))
+
+	_, err := evalString(env, `
+(def g (fn (x & more) (fail x)))
+(def f (fn (y) (g y :extra)))
+(f 1)
+`)
+
+	var pe parens.Error
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected parens.Error, got %#v", err)
+	}
+
+	var names []string
+	for _, frame := range pe.StackTrace() {
+		names = append(names, frame.Name)
+	}
+	assertEqual(t, []string{"fail", "g", "f"}, names)
+
+	want := "<string>:2:23: failed" +
+		"\n  at (fail 1) [<string>:2:23]" +
+		"\n  at (g 1 :extra) [<string>:3:16]" +
+		"\n  at (f 1) [<string>:4:1]"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("got=%q\nwant=%q", got, want)
+	}
+
+	if got := fmt.Sprintf("%v", err); got != "<string>:2:23: failed" {
+		t.Errorf("got=%q\nwant=%q", got, "<string>:2:23: failed")
+	}
+}
+
+func TestError_StackTrace_ListCallHead(t *testing.T) {
+	t.Parallel()
+
+	env := parens.New(parens.WithGlobals(map[string]parens.Any{
+		"fail": parens.GoFunc(func(_ *parens.Env, _ ...parens.Any) (parens.Any, error) {
+			return nil, errors.New("failed")
+		}),
+	}, nil))
+
+	_, err := evalString(env, `
+(def mk (fn (n) (fn (x) (fail x))))
+((mk 2) :a)
+`)
+
+	want := "<string>:2:25: failed" +
+		"\n  at (fail :a) [<string>:2:25]" +
+		"\n  at ((mk 2) :a) [<string>:3:1]"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("got=%q\nwant=%q", got, want)
+	}
+}
+
+func TestError_Error(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("cause")
+	assertEqual(t, "cause: message", parens.Error{Cause: cause, Message: "message"}.Error())
+	assertEqual(t, "cause", parens.Error{Cause: cause}.Error())
+	assertEqual(t, "message", parens.Error{Message: "message"}.Error())
+
+	pos := parens.Position{File: "test.lisp", Ln: 1, Col: 2}
+	assertEqual(t, "test.lisp:1:2: cause", parens.Error{Cause: cause, Pos: pos}.Error())
+}
+
+func TestError_Pos(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title string
+		src   string
+		want  string
+	}{
+		{
+			title: "Unknown Symbol In Invocation",
+			src:   "(def f (fn (x) x))\n(f 1\n   unknown)",
+			want:  "<string>:3:4",
+		},
+		{
+			title: "Unknown Top Level Symbol",
+			src:   "(def x 1)\n  unknown",
+			want:  "<string>:2:3",
+		},
+		{
+			title: "Unknown Symbol In Vector",
+			src:   "[1\n undefined-sym]",
+			want:  "<string>:2:2",
+		},
+		{
+			title: "Unknown Symbol In Nested Vector",
+			src:   "(do\n  [1 [undefined-sym]])",
+			want:  "<string>:2:7",
+		},
+		{
+			title: "Unknown Symbol In Map",
+			src:   "(do\n  {:a undefined-sym})",
+			want:  "<string>:2:3",
+		},
+		{
+			title: "Invalid Special Form",
+			src:   "(do\n  (if))",
+			want:  "<string>:2:3",
+		},
+		{
+			title: "Not Invokable",
+			src:   "(do\n  (:key))",
+			want:  "<string>:2:3",
+		},
+		{
+			title: "Duplicate Map Key",
+			src:   "(def x :a)\n(do\n  {x 1 :a 2})",
+			want:  "<string>:3:3",
+		},
+		{
+			title: "Duplicate Set Item",
+			src:   "(def x :a)\n(do\n  #{x :a})",
+			want:  "<string>:3:4",
+		},
+		{
+			title: "Failure Within Function Body",
+			src:   "(def f (fn (x)\n  (x)))\n(f 1)",
+			want:  "<string>:2:3",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			_, err := evalString(parens.New(), tt.src)
+
+			var pe parens.Error
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected parens.Error, got %#v", err)
+			}
+			assertEqual(t, tt.want, pe.Pos.String())
+		})
+	}
+}
@@ -22,6 +22,25 @@ var (
 	// ErrNumberFormat is returned when a reader macro encounters a illegally
 	// formatted numerical form.
 	ErrNumberFormat = errors.New("invalid number format")
+
+	// ErrInvalidEscape is returned when a string literal contains an unknown
+	// or malformed escape sequence.
+	ErrInvalidEscape = errors.New("invalid escape sequence")
+
+	// ErrInvalidMeta is returned when the metadata form is not a map, keyword,
+	// symbol or string or when the form following it cannot carry metadata.
+	ErrInvalidMeta = errors.New("invalid metadata")
+
+	// ErrInvalidRegex is returned when a regex literal does not compile.
+	ErrInvalidRegex = errors.New("invalid regex")
+
+	// ErrNestedFnLiteral is returned when an anonymous function literal #()
+	// appears within another.
+	ErrNestedFnLiteral = errors.New("nested #() are not allowed")
+
+	// ErrInvalidFnArg is returned when an argument literal within #() is not
+	// one of %, %& or %n with n >= 1.
+	ErrInvalidFnArg = errors.New("invalid fn literal argument")
 )
 
 // Error is returned by all parens operations. Cause indicates the underlying
@@ -4,8 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
+	"reflect"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/spy16/parens"
 )
@@ -14,66 +18,6 @@ import (
 // or customize behavior of the reader.
 type Macro func(rd *Reader, init rune) (parens.Any, error)
 
-// // TODO(enhancement):  implement parens.Set
-// // SetReader implements the reader macro for reading set from source.
-// func SetReader(setEnd rune, factory func() parens.Set) Macro {
-// 	return func(rd *Reader, _ rune) (parens.Any, error) {
-// 		forms, err := rd.Container(setEnd, "Set")
-// 		if err != nil {
-// 			return nil, err
-// 		}
-// 		return factory().Conj(forms...), nil
-// 	}
-// }
-
-// // TODO(enhancement): implement parens.Vector
-// // VectorReader implements the reader macro for reading vector from source.
-// func VectorReader(vecEnd rune, factory func() parens.Vector) Macro {
-// 	return func(rd *Reader, _ rune) (parens.Any, error) {
-// 		forms, err := rd.Container(vecEnd, "Vector")
-// 		if err != nil {
-// 			return nil, err
-// 		}
-
-// 		vec := factory()
-// 		for _, f := range forms {
-// 			_ = f
-// 		}
-
-// 		return vec, nil
-// 	}
-// }
-
-// // TODO(enhancement) implement parens.Map
-// // MapReader returns a reader macro for reading map values from source. factory
-// // is used to construct the map and `Assoc` is called for every pair read.
-// func MapReader(mapEnd rune, factory func() parens.Map) Macro {
-// 	return func(rd *Reader, _ rune) (parens.Any, error) {
-// 		forms, err := rd.Container(mapEnd, "Map")
-// 		if err != nil {
-// 			return nil, err
-// 		}
-
-// 		if len(forms)%2 != 0 {
-// 			return nil, errors.New("expecting even number of forms within {}")
-// 		}
-
-// 		m := factory()
-// 		for i := 0; i < len(forms); i += 2 {
-// 			if m.HasKey(forms[i]) {
-// 				return nil, fmt.Errorf("duplicate key: %v", forms[i])
-// 			}
-
-// 			m, err = m.Assoc(forms[i], forms[i+1])
-// 			if err != nil {
-// 				return nil, err
-// 			}
-// 		}
-
-// 		return m, nil
-// 	}
-// }
-
 // UnmatchedDelimiter implements a reader macro that can be used to capture
 // unmatched delimiters such as closing parenthesis etc.
 func UnmatchedDelimiter() Macro {
@@ -112,9 +56,13 @@ func readNumber(rd *Reader, init rune) (parens.Any, error) {
 	decimalPoint := strings.ContainsRune(numStr, '.')
 	isRadix := strings.ContainsRune(numStr, 'r')
 	isScientific := strings.ContainsRune(numStr, 'e')
+	isRatio := strings.ContainsRune(numStr, '/')
+	isBigInt := strings.HasSuffix(numStr, "N")
 
 	switch {
-	case isRadix && (decimalPoint || isScientific):
+	case isRadix && (decimalPoint || isScientific),
+		(isRatio || isBigInt) && (decimalPoint || isScientific || isRadix),
+		isRatio && isBigInt:
 		return nil, rd.annotateErr(ErrNumberFormat, beginPos, numStr)
 
 	case isScientific:
@@ -138,8 +86,29 @@ func readNumber(rd *Reader, init rune) (parens.Any, error) {
 		}
 		return v, nil
 
+	case isRatio:
+		v, err := parseRatio(numStr)
+		if err != nil {
+			return nil, rd.annotateErr(err, beginPos, numStr)
+		}
+		return v, nil
+
+	case isBigInt:
+		v, ok := new(big.Int).SetString(strings.TrimSuffix(numStr, "N"), 0)
+		if !ok {
+			return nil, rd.annotateErr(ErrNumberFormat, beginPos, numStr)
+		}
+		return parens.NewBigInt(v), nil
+
 	default:
 		v, err := strconv.ParseInt(numStr, 0, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			// promote to BigInt on overflow.
+			if bi, ok := new(big.Int).SetString(numStr, 0); ok {
+				return parens.NewBigInt(bi), nil
+			}
+		}
+
 		if err != nil {
 			return nil, rd.annotateErr(ErrNumberFormat, beginPos, numStr)
 		}
@@ -161,31 +130,185 @@ func readString(rd *Reader, init rune) (parens.Any, error) {
 			return nil, rd.annotateErr(err, beginPos, string(init)+b.String())
 		}
 
-		if r == '\\' {
-			r2, err := rd.NextRune()
+		if r == '"' {
+			break
+		} else if r == '\\' {
+			escaped, err := readEscape(rd)
 			if err != nil {
-				if errors.Is(err, io.EOF) {
-					err = ErrEOF
-				}
-
 				return nil, rd.annotateErr(err, beginPos, string(init)+b.String())
 			}
+			b.WriteString(escaped)
+			continue
+		}
 
-			// TODO: Support for Unicode escape \uNN format.
+		b.WriteRune(r)
+	}
 
-			escaped, err := getEscape(r2)
-			if err != nil {
-				return nil, err
+	return parens.String(b.String()), nil
+}
+
+// readEscape reads an escape sequence within a string literal after the '\'
+// and returns the text it represents. In addition to the simple escapes (\n,
+// \t etc.), \uXXXX and \UXXXXXXXX represent unicode code points while \xNN and
+// \NNN (octal) represent a single byte, as in Go string literals.
+func readEscape(rd *Reader) (string, error) {
+	beginPos := rd.Position()
+
+	r, err := rd.NextRune()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = ErrEOF
+		}
+		return "", rd.annotateErr(err, beginPos, `\`)
+	}
+
+	var digits, base int
+	isByte := false
+	switch {
+	case r == 'u':
+		digits, base = 4, 16
+	case r == 'U':
+		digits, base = 8, 16
+	case r == 'x':
+		digits, base, isByte = 2, 16, true
+	case '0' <= r && r <= '7':
+		digits, base, isByte = 3, 8, true
+		rd.Unread(r)
+	default:
+		escaped, err := getEscape(r)
+		if err != nil {
+			return "", rd.annotateErr(err, beginPos, `\`+string(r))
+		}
+		return string(escaped), nil
+	}
+
+	seq := []rune{'\\'}
+	if base != 8 {
+		seq = append(seq, r)
+	}
+
+	var val uint64
+	for i := 0; i < digits; i++ {
+		d, err := rd.NextRune()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = ErrEOF
 			}
-			r = escaped
-		} else if r == '"' {
-			break
+			return "", rd.annotateErr(err, beginPos, string(seq))
+		}
+
+		n, ok := digitVal(d)
+		if !ok || n >= base {
+			// the offending rune is not part of the escape (e.g., closing quote).
+			rd.Unread(d)
+			return "", rd.annotateErr(fmt.Errorf("%w '%s'", ErrInvalidEscape, string(seq)), beginPos, string(seq))
 		}
+		seq = append(seq, d)
+		val = val*uint64(base) + uint64(n)
+	}
+
+	if isByte {
+		if val > 0xFF {
+			return "", rd.annotateErr(fmt.Errorf("%w '%s'", ErrInvalidEscape, string(seq)), beginPos, string(seq))
+		}
+		return string([]byte{byte(val)}), nil
+	}
+
+	if val > unicode.MaxRune || !utf8.ValidRune(rune(val)) {
+		return "", rd.annotateErr(fmt.Errorf("%w '%s'", ErrInvalidEscape, string(seq)), beginPos, string(seq))
+	}
+	return string(rune(val)), nil
+}
+
+func digitVal(r rune) (int, bool) {
+	switch {
+	case '0' <= r && r <= '9':
+		return int(r - '0'), true
+	case 'a' <= r && r <= 'f':
+		return int(r-'a') + 10, true
+	case 'A' <= r && r <= 'F':
+		return int(r-'A') + 10, true
+	}
+	return 0, false
+}
+
+// readRegex reads a regular expression literal of the form #"pattern". The
+// pattern is read as is (escape sequences are not processed) except that \"
+// does not terminate the literal.
+func readRegex(rd *Reader, init rune) (parens.Any, error) {
+	beginPos := rd.Position()
 
+	var b strings.Builder
+	escaped := false
+	for {
+		r, err := rd.NextRune()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = ErrEOF
+			}
+			return nil, rd.annotateErr(err, beginPos, "#"+string(init)+b.String())
+		}
+
+		if r == '"' && !escaped {
+			break
+		}
+		escaped = r == '\\' && !escaped
 		b.WriteRune(r)
 	}
 
-	return parens.String(b.String()), nil
+	re, err := parens.NewRegex(b.String())
+	if err != nil {
+		return nil, rd.annotateErr(fmt.Errorf("%w: %v", ErrInvalidRegex, err), beginPos, b.String())
+	}
+	return re, nil
+}
+
+// readDiscard reads and discards the form following #_. Consecutive discards
+// (e.g., #_ #_ a b) discard as many forms.
+func readDiscard(rd *Reader, _ rune) (parens.Any, error) {
+	beginPos := rd.Position()
+
+	for {
+		_, err := rd.readOne()
+		if err == nil {
+			return nil, ErrSkip
+		} else if err != ErrSkip {
+			if errors.Is(err, io.EOF) {
+				err = ErrEOF
+			}
+			return nil, rd.annotateErr(err, beginPos, "#_")
+		}
+	}
+}
+
+// readBlockComment skips a block comment of the form #| ... |#. Block comments
+// can be nested.
+func readBlockComment(rd *Reader, _ rune) (parens.Any, error) {
+	beginPos := rd.Position()
+
+	depth := 1
+	var prev rune
+	for depth > 0 {
+		r, err := rd.NextRune()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = ErrEOF
+			}
+			return nil, rd.annotateErr(err, beginPos, "#|")
+		}
+
+		switch {
+		case prev == '#' && r == '|':
+			depth++
+			r = 0 // '|' must not close the comment as part of "|#".
+		case prev == '|' && r == '#':
+			depth--
+			r = 0
+		}
+		prev = r
+	}
+
+	return nil, ErrSkip
 }
 
 func readComment(rd *Reader, _ rune) (parens.Any, error) {
@@ -250,34 +373,292 @@ func readList(rd *Reader, _ rune) (parens.Any, error) {
 	beginPos := rd.Position()
 
 	forms := make([]parens.Any, 0, 32) // pre-allocate to improve performance on small lists
-	if err := rd.Container(listEnd, "list", func(val parens.Any) error {
+	spans := make([]parens.Span, 0, 32)
+	if err := rd.container(listEnd, "list", func(val parens.Any, span parens.Span) error {
 		forms = append(forms, val)
+		spans = append(spans, span)
 		return nil
 	}); err != nil {
 		return nil, rd.annotateErr(err, beginPos, "")
 	}
 
-	return parens.NewList(forms...), nil
+	span := parens.Span{Begin: beginPos, End: rd.Position()}
+	return parens.NewPositionalList(span, forms, spans), nil
 }
 
-func quoteFormReader(expandFunc string) Macro {
-	return func(rd *Reader, _ rune) (parens.Any, error) {
-		expr, err := rd.One()
+// readFnLiteral reads an anonymous function literal of the form #(body...)
+// and returns it as (fn [%1 %2 ... & %&] (body...)). Parameters are inferred
+// from the highest %n and %& used in the body. % is same as %1. Nested
+// function literals are not allowed.
+func readFnLiteral(rd *Reader, init rune) (parens.Any, error) {
+	beginPos := rd.Position()
+
+	if rd.fnLiteral {
+		return nil, rd.annotateErr(ErrNestedFnLiteral, beginPos, "#(")
+	}
+
+	var maxArg int
+	var variadic bool
+
+	symReader := rd.symReader
+	rd.symReader = func(rd *Reader, init rune) (parens.Any, error) {
+		beginPos := rd.Position()
+
+		form, err := symReader(rd, init)
 		if err != nil {
-			if err == io.EOF {
-				return nil, Error{
-					Form:  expandFunc,
-					Cause: ErrEOF,
-				}
-			} else if err == ErrSkip {
-				return nil, Error{
-					Form:  expandFunc,
-					Cause: errors.New("cannot quote a no-op form"),
-				}
-			}
 			return nil, err
 		}
 
-		return parens.NewList(parens.Symbol(expandFunc), expr), nil
+		sym, ok := form.(parens.Symbol)
+		if !ok || !strings.HasPrefix(string(sym), "%") {
+			return form, nil
+		}
+
+		switch sym {
+		case "%":
+			sym = "%1"
+		case "%&":
+			variadic = true
+			return sym, nil
+		}
+
+		n, err := strconv.Atoi(string(sym[1:]))
+		if err != nil {
+			// not an argument literal (e.g., %foo).
+			return sym, nil
+		} else if n < 1 {
+			return nil, rd.annotateErr(ErrInvalidFnArg, beginPos, string(sym))
+		}
+
+		if n > maxArg {
+			maxArg = n
+		}
+		return sym, nil
+	}
+
+	rd.fnLiteral = true
+	defer func() {
+		rd.fnLiteral = false
+		rd.symReader = symReader
+	}()
+
+	body, err := readList(rd, init)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]parens.Any, 0, maxArg+2)
+	for i := 1; i <= maxArg; i++ {
+		params = append(params, parens.Symbol(fmt.Sprintf("%%%d", i)))
+	}
+	if variadic {
+		params = append(params, parens.Symbol("&"), parens.Symbol("%&"))
+	}
+
+	bodySpan := body.(parens.Positional).Span()
+	span := parens.Span{Begin: beginPos, End: rd.Position()}
+	return parens.NewPositionalList(span,
+		[]parens.Any{parens.Symbol("fn"), parens.NewVector(params...), body},
+		[]parens.Span{span, span, bodySpan},
+	), nil
+}
+
+func readVector(rd *Reader, _ rune) (parens.Any, error) {
+	const vecEnd = ']'
+
+	beginPos := rd.Position()
+
+	var forms []parens.Any
+	var spans []parens.Span
+	if err := rd.container(vecEnd, "vector", func(val parens.Any, span parens.Span) error {
+		forms = append(forms, val)
+		spans = append(spans, span)
+		return nil
+	}); err != nil {
+		return nil, rd.annotateErr(err, beginPos, "")
+	}
+
+	span := parens.Span{Begin: beginPos, End: rd.Position()}
+	return parens.NewPositionalVector(span, forms, spans), nil
+}
+
+func readMap(rd *Reader, _ rune) (parens.Any, error) {
+	const mapEnd = '}'
+
+	beginPos := rd.Position()
+
+	var forms, keys []parens.Any
+	var keySpans []parens.Span
+	if err := rd.container(mapEnd, "map", func(val parens.Any, span parens.Span) error {
+		if len(forms)%2 == 0 {
+			keys = append(keys, val)
+			keySpans = append(keySpans, span)
+		}
+		forms = append(forms, val)
+		return nil
+	}); err != nil {
+		return nil, rd.annotateErr(err, beginPos, "")
+	}
+
+	if len(forms)%2 != 0 {
+		return nil, rd.annotateErr(errors.New("expecting even number of forms within {}"), beginPos, "")
+	}
+
+	span := parens.Span{Begin: beginPos, End: rd.Position()}
+	m, err := parens.NewPositionalMap(span, forms...)
+	if err != nil {
+		return nil, rd.annotateErr(err, beginPos, "")
+	} else if m.Size() != len(keys) {
+		return nil, duplicateKeyErr(keys, keySpans)
+	}
+
+	return m, nil
+}
+
+func readSet(rd *Reader, _ rune) (parens.Any, error) {
+	const setEnd = '}'
+
+	beginPos := rd.Position()
+
+	var forms []parens.Any
+	var spans []parens.Span
+	if err := rd.container(setEnd, "set", func(val parens.Any, span parens.Span) error {
+		forms = append(forms, val)
+		spans = append(spans, span)
+		return nil
+	}); err != nil {
+		return nil, rd.annotateErr(err, beginPos, "")
+	}
+
+	span := parens.Span{Begin: beginPos, End: rd.Position()}
+	set, err := parens.NewPositionalSet(span, forms...)
+	if err != nil {
+		return nil, rd.annotateErr(err, beginPos, "")
+	} else if set.Size() != len(forms) {
+		return nil, duplicateKeyErr(forms, spans)
+	}
+
+	return set, nil
+}
+
+func readUnquote(rd *Reader, _ rune) (parens.Any, error) {
+	beginPos := rd.Position()
+
+	r, err := rd.NextRune()
+	if err == nil {
+		if r == '@' {
+			return readQuoted(rd, "unquote-splicing", beginPos)
+		}
+		rd.Unread(r)
+	}
+
+	// EOF (if any) is reported while reading the quoted form.
+	return readQuoted(rd, "unquote", beginPos)
+}
+
+// readMeta reads metadata of the form ^meta followed by a form and returns the
+// form with the metadata merged into its existing metadata. meta can be a map,
+// a keyword (^:private is same as ^{:private true}) or a symbol or string (^T
+// is same as ^{:tag T}). The form must implement parens.WithMeta.
+func readMeta(rd *Reader, init rune) (parens.Any, error) {
+	beginPos := rd.Position()
+
+	meta, err := rd.One()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = ErrEOF
+		}
+		return nil, rd.annotateErr(err, beginPos, string(init))
+	}
+
+	var m *parens.Map
+	switch v := meta.(type) {
+	case *parens.Map:
+		m = v
+	case parens.Keyword:
+		m, err = parens.NewMap(v, parens.Bool(true))
+	case parens.Symbol, parens.String:
+		m, err = parens.NewMap(parens.Keyword("tag"), v)
+	default:
+		err = fmt.Errorf("%w: metadata must be map, keyword, symbol or string, not '%s'",
+			ErrInvalidMeta, reflect.TypeOf(meta))
+	}
+	if err != nil {
+		return nil, rd.annotateErr(err, beginPos, string(init))
+	}
+
+	form, err := rd.One()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = ErrEOF
+		}
+		return nil, rd.annotateErr(err, beginPos, string(init))
+	}
+
+	if _, ok := form.(parens.WithMeta); !ok {
+		err = fmt.Errorf("%w: cannot attach metadata to '%s'", ErrInvalidMeta, reflect.TypeOf(form))
+		return nil, rd.annotateErr(err, beginPos, string(init))
+	}
+	return parens.MergeMeta(form, m)
+}
+
+// duplicateKeyErr returns error with ErrDuplicateKey cause positioned at the
+// first of the keys that is equal to one of the keys before it.
+func duplicateKeyErr(keys []parens.Any, spans []parens.Span) error {
+	seen := &parens.Map{}
+	for i, key := range keys {
+		found, err := seen.HasKey(key)
+		if err != nil {
+			return err
+		} else if found {
+			return Error{
+				Cause: fmt.Errorf("%w: %s", parens.ErrDuplicateKey, sexpr(key)),
+				Begin: spans[i].Begin,
+				End:   spans[i].End,
+			}
+		}
+
+		if seen, err = seen.Assoc(key, parens.Nil{}); err != nil {
+			return err
+		}
 	}
+	return nil
+}
+
+// sexpr returns the s-expression of the form for use in error messages.
+func sexpr(form parens.Any) string {
+	if sx, ok := form.(parens.SExpressable); ok {
+		if s, err := sx.SExpr(); err == nil {
+			return s
+		}
+	}
+	return fmt.Sprintf("%v", form)
+}
+
+func quoteFormReader(expandFunc string) Macro {
+	return func(rd *Reader, _ rune) (parens.Any, error) {
+		return readQuoted(rd, expandFunc, rd.Position())
+	}
+}
+
+// readQuoted reads the next form and returns (expandFunc form) list.
+func readQuoted(rd *Reader, expandFunc string, beginPos Position) (parens.Any, error) {
+	expr, err := rd.One()
+	if err != nil {
+		if err == io.EOF {
+			return nil, Error{
+				Form:  expandFunc,
+				Cause: ErrEOF,
+			}
+		} else if err == ErrSkip {
+			return nil, Error{
+				Form:  expandFunc,
+				Cause: errors.New("cannot quote a no-op form"),
+			}
+		}
+		return nil, err
+	}
+
+	span := parens.Span{Begin: beginPos, End: rd.Position()}
+	return parens.NewPositionalList(span, []parens.Any{parens.Symbol(expandFunc), expr}, nil), nil
 }
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"math/big"
 	"net"
 	"os"
 	"reflect"
@@ -29,7 +30,7 @@ var (
 		'\\': '\\',
 		't':  '\t',
 		'a':  '\a',
-		'f':  '\a',
+		'f':  '\f',
 		'r':  '\r',
 		'b':  '\b',
 		'v':  '\v',
@@ -60,11 +61,22 @@ func New(r io.Reader, opts ...Option) *Reader {
 			'\\': readCharacter,
 			'(':  readList,
 			')':  UnmatchedDelimiter(),
+			'[':  readVector,
+			']':  UnmatchedDelimiter(),
+			'{':  readMap,
+			'}':  UnmatchedDelimiter(),
 			'\'': quoteFormReader("quote"),
-			'~':  quoteFormReader("unquote"),
+			'~':  readUnquote,
 			'`':  quoteFormReader("syntax-quote"),
+			'^':  readMeta,
+		},
+		dispatch: map[rune]Macro{
+			'{': readSet,
+			'"': readRegex,
+			'(': readFnLiteral,
+			'_': readDiscard,
+			'|': readBlockComment,
 		},
-		dispatch: map[rune]Macro{},
 	}
 
 	for _, option := range withDefaults(opts) {
@@ -85,9 +97,16 @@ type Reader struct {
 	line, col            int
 	lastCol              int
 	dispatching          bool
+	fnLiteral            bool
 	dispatch             map[rune]Macro
 	macros               map[rune]Macro
 	numReader, symReader Macro
+
+	// spans of the top-level forms read so far. nesting is the number of
+	// calls to One in progress, which is above one for the forms read by
+	// macros.
+	spans   []parens.Span
+	nesting int
 }
 
 // All consumes characters from stream until EOF and returns a list of all the forms
@@ -114,7 +133,18 @@ func (rd *Reader) All() ([]parens.Any, error) {
 // errors will be wrapped with reader Error type along with the positional information
 // obtained using Position().
 func (rd *Reader) One() (parens.Any, error) {
+	rd.nesting++
+	defer func() { rd.nesting-- }()
+
 	for {
+		if err := rd.SkipSpaces(); err != nil {
+			return nil, err
+		}
+
+		// position of the reader is at the rune preceding the form.
+		begin := rd.Position()
+		begin.Col++
+
 		form, err := rd.readOne()
 		if err != nil {
 			if errors.Is(err, ErrSkip) {
@@ -122,10 +152,21 @@ func (rd *Reader) One() (parens.Any, error) {
 			}
 			return nil, err
 		}
+
+		if rd.nesting == 1 {
+			rd.spans = append(rd.spans, parens.Span{Begin: begin, End: rd.Position()})
+		}
 		return form, nil
 	}
 }
 
+// Spans returns the source spans of the top-level forms returned by One and All
+// so far, in the same order. Lists, vectors etc. also carry their span (see
+// parens.Positional), but symbols and other values read at the top level have no
+// enclosing form to report their position. Use parens.WithPos to position the
+// errors from evaluating such forms.
+func (rd *Reader) Spans() []parens.Span { return rd.spans }
+
 // IsTerminal returns true if the rune should terminate a form. Macro trigger runes
 // defined in the read table and all whitespace characters are considered terminal.
 // "," is also considered a whitespace character and hence a terminal.
@@ -271,7 +312,15 @@ func (rd *Reader) Token(init rune) (string, error) {
 
 // Container reads multiple forms until 'end' rune is reached. Should be used to read
 // collection types like List etc. formType is only used to annotate errors.
-func (rd Reader) Container(end rune, formType string, f func(parens.Any) error) error {
+func (rd *Reader) Container(end rune, formType string, f func(parens.Any) error) error {
+	return rd.container(end, formType, func(form parens.Any, _ parens.Span) error {
+		return f(form)
+	})
+}
+
+// container is same as Container but also passes the source span of each form
+// read to f.
+func (rd *Reader) container(end rune, formType string, f func(parens.Any, parens.Span) error) error {
 	for {
 		if err := rd.SkipSpaces(); err != nil {
 			if err == io.EOF {
@@ -293,6 +342,10 @@ func (rd Reader) Container(end rune, formType string, f func(parens.Any) error)
 		}
 		rd.Unread(r)
 
+		// position of the reader is at the rune preceding the form.
+		begin := rd.Position()
+		begin.Col++
+
 		expr, err := rd.readOne()
 		if err != nil {
 			if err == ErrSkip {
@@ -302,7 +355,7 @@ func (rd Reader) Container(end rune, formType string, f func(parens.Any) error)
 		}
 
 		// TODO(performance):  verify `f` is inlined by the compiler
-		if err = f(expr); err != nil {
+		if err = f(expr, parens.Span{Begin: begin, End: rd.Position()}); err != nil {
 			return err
 		}
 	}
@@ -312,6 +365,13 @@ func (rd Reader) Container(end rune, formType string, f func(parens.Any) error)
 
 // readOne is same as One() but always returns un-annotated errors.
 func (rd *Reader) readOne() (parens.Any, error) {
+	if rd.dispatching {
+		// forms nested within a dispatch form (e.g., items of a set) are not
+		// terminated by the dispatch runes.
+		rd.dispatching = false
+		defer func() { rd.dispatching = true }()
+	}
+
 	if err := rd.SkipSpaces(); err != nil {
 		return nil, err
 	}
@@ -385,6 +445,10 @@ func (rd *Reader) annotateErr(err error, beginPos Position, form string) error {
 
 	readErr := Error{}
 	if e, ok := err.(Error); ok {
+		if e.Begin != (Position{}) {
+			// already annotated by a nested form, which is more precise.
+			return e
+		}
 		readErr = e
 	} else {
 		readErr = Error{Cause: err}
@@ -409,15 +473,15 @@ func readUnicodeChar(token string, base int) (parens.Char, error) {
 	return parens.Char(num), nil
 }
 
-func parseRadix(numStr string) (parens.Int64, error) {
+func parseRadix(numStr string) (parens.Any, error) {
 	parts := strings.Split(numStr, "r")
 	if len(parts) != 2 {
-		return 0, fmt.Errorf("%w (radix notation): '%s'", ErrNumberFormat, numStr)
+		return nil, fmt.Errorf("%w (radix notation): '%s'", ErrNumberFormat, numStr)
 	}
 
 	base, err := strconv.ParseInt(parts[0], 10, 64)
 	if err != nil {
-		return 0, fmt.Errorf("%w (radix notation): '%s'", ErrNumberFormat, numStr)
+		return nil, fmt.Errorf("%w (radix notation): '%s'", ErrNumberFormat, numStr)
 	}
 
 	repr := parts[1]
@@ -427,13 +491,43 @@ func parseRadix(numStr string) (parens.Int64, error) {
 	}
 
 	v, err := strconv.ParseInt(repr, int(base), 64)
+	if errors.Is(err, strconv.ErrRange) {
+		// promote to BigInt on overflow.
+		if bi, ok := new(big.Int).SetString(repr, int(base)); ok {
+			return parens.NewBigInt(bi), nil
+		}
+	}
+
 	if err != nil {
-		return 0, fmt.Errorf("%w (radix notation): '%s'", ErrNumberFormat, numStr)
+		return nil, fmt.Errorf("%w (radix notation): '%s'", ErrNumberFormat, numStr)
 	}
 
 	return parens.Int64(v), nil
 }
 
+// parseRatio parses a ratio of the form 'n/d'. Returns an integer if the
+// ratio reduces to one.
+func parseRatio(numStr string) (parens.Any, error) {
+	parts := strings.Split(numStr, "/")
+	if len(parts) != 2 || strings.ContainsAny(parts[1], "+-") {
+		return nil, fmt.Errorf("%w (ratio): '%s'", ErrNumberFormat, numStr)
+	}
+
+	num, okNum := new(big.Int).SetString(parts[0], 10)
+	den, okDen := new(big.Int).SetString(parts[1], 10)
+	if !okNum || !okDen || den.Sign() == 0 {
+		return nil, fmt.Errorf("%w (ratio): '%s'", ErrNumberFormat, numStr)
+	}
+
+	r := new(big.Rat).SetFrac(num, den)
+	if !r.IsInt() {
+		return parens.NewRatio(r), nil
+	} else if r.Num().IsInt64() {
+		return parens.Int64(r.Num().Int64()), nil
+	}
+	return parens.NewBigInt(r.Num()), nil
+}
+
 func parseScientific(numStr string) (parens.Float64, error) {
 	parts := strings.Split(numStr, "e")
 	if len(parts) != 2 {
@@ -456,7 +550,7 @@ func parseScientific(numStr string) (parens.Float64, error) {
 func getEscape(r rune) (rune, error) {
 	escaped, found := escapeMap[r]
 	if !found {
-		return -1, fmt.Errorf("illegal escape sequence '\\%c'", r)
+		return -1, fmt.Errorf("%w '\\%c'", ErrInvalidEscape, r)
 	}
 
 	return escaped, nil
@@ -487,15 +581,4 @@ func inferFileName(rs io.Reader) string {
 
 // Position represents the positional information about a value read
 // by reader.
-type Position struct {
-	File string
-	Ln   int
-	Col  int
-}
-
-func (p Position) String() string {
-	if p.File == "" {
-		p.File = "<unknown>"
-	}
-	return fmt.Sprintf("%s:%d:%d", p.File, p.Ln, p.Col)
-}
+type Position = parens.Position
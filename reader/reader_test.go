@@ -2,7 +2,9 @@ package reader
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"math/big"
 	"os"
 	"reflect"
 	"strings"
@@ -175,6 +177,51 @@ func TestReader_All(t *testing.T) {
 			src:     `())`,
 			wantErr: true,
 		},
+		{
+			name: "Discard",
+			src:  `:a #_ :b :c #_(foo [bar]) :d`,
+			want: []parens.Any{parens.Keyword("a"), parens.Keyword("c"), parens.Keyword("d")},
+		},
+		{
+			name: "ConsecutiveDiscards",
+			src:  `#_ #_ :a :b :c`,
+			want: []parens.Any{parens.Keyword("c")},
+		},
+		{
+			name: "DiscardSkipsComment",
+			src:  "#_ ; comment\n :a :b",
+			want: []parens.Any{parens.Keyword("b")},
+		},
+		{
+			name: "DiscardSymbolWithUnderscore",
+			src:  `#_ a_b c_d`,
+			want: []parens.Any{parens.Symbol("c_d")},
+		},
+		{
+			name:    "DiscardEOF",
+			src:     `:a #_`,
+			wantErr: true,
+		},
+		{
+			name: "BlockComment",
+			src:  "#| multi\nline |# :a #|:b|# :c",
+			want: []parens.Any{parens.Keyword("a"), parens.Keyword("c")},
+		},
+		{
+			name: "NestedBlockComment",
+			src:  `#| a #| (b) |# c |# :d`,
+			want: []parens.Any{parens.Keyword("d")},
+		},
+		{
+			name: "BlockCommentPipeHash",
+			src:  `#| a ||# :b`,
+			want: []parens.Any{parens.Keyword("b")},
+		},
+		{
+			name:    "UnterminatedBlockComment",
+			src:     `:a #| #| b |#`,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -219,6 +266,33 @@ func TestReader_One(t *testing.T) {
 				),
 			),
 		},
+		{
+			name: "UnQuoteSplicing",
+			src:  "~@(x 3)",
+			want: parens.NewList(
+				parens.Symbol("unquote-splicing"),
+				parens.NewList(
+					parens.Symbol("x"),
+					parens.Int64(3),
+				),
+			),
+		},
+		{
+			name:    "UnQuoteEOF",
+			src:     "~",
+			wantErr: true,
+		},
+		{
+			name: "SyntaxQuote",
+			src:  "`(x ~y)",
+			want: parens.NewList(
+				parens.Symbol("syntax-quote"),
+				parens.NewList(
+					parens.Symbol("x"),
+					parens.NewList(parens.Symbol("unquote"), parens.Symbol("y")),
+				),
+			),
+		},
 	})
 }
 
@@ -375,9 +449,77 @@ func TestReader_One_Number(t *testing.T) {
 			src:     "9.3.2",
 			wantErr: true,
 		},
+		{
+			name: "BigIntSuffix",
+			src:  "12N",
+			want: parens.NewBigInt(big.NewInt(12)),
+		},
+		{
+			name: "NegativeHexBigInt",
+			src:  "-0xFFN",
+			want: parens.NewBigInt(big.NewInt(-255)),
+		},
+		{
+			name: "IntOverflowPromoted",
+			src:  "9223372036854775808",
+			want: parens.NewBigInt(mustBigInt("9223372036854775808")),
+		},
+		{
+			name: "NegativeIntOverflowPromoted",
+			src:  "-9223372036854775809",
+			want: parens.NewBigInt(mustBigInt("-9223372036854775809")),
+		},
+		{
+			name: "RadixOverflowPromoted",
+			src:  "16r10000000000000000",
+			want: parens.NewBigInt(mustBigInt("18446744073709551616")),
+		},
+		{
+			name: "Ratio",
+			src:  "22/7",
+			want: parens.NewRatio(big.NewRat(22, 7)),
+		},
+		{
+			name: "NegativeRatioReduced",
+			src:  "-6/4",
+			want: parens.NewRatio(big.NewRat(-3, 2)),
+		},
+		{
+			name: "RatioReducedToInt",
+			src:  "4/2",
+			want: parens.Int64(2),
+		},
+		{
+			name:    "RatioZeroDenominator",
+			src:     "1/0",
+			wantErr: true,
+		},
+		{
+			name:    "RatioNegativeDenominator",
+			src:     "1/-2",
+			wantErr: true,
+		},
+		{
+			name:    "RatioWithDecimal",
+			src:     "1.5/2",
+			wantErr: true,
+		},
+		{
+			name:    "BigIntWithDecimal",
+			src:     "1.5N",
+			wantErr: true,
+		},
 	})
 }
 
+func mustBigInt(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid big int: " + s)
+	}
+	return v
+}
+
 func TestReader_One_String(t *testing.T) {
 	executeReaderTests(t, []readerTestCase{
 		{
@@ -415,9 +557,158 @@ func TestReader_One_String(t *testing.T) {
 			src:     `"hello\`,
 			wantErr: true,
 		},
+		{
+			name: "EscapeFormFeed",
+			src:  `"a\fb"`,
+			want: parens.String("a\fb"),
+		},
+		{
+			name: "EscapeUnicode",
+			src:  `"\u03bb\u00E9"`,
+			want: parens.String("λé"),
+		},
+		{
+			name: "EscapeLongUnicode",
+			src:  `"\U0001F600!"`,
+			want: parens.String("\U0001F600!"),
+		},
+		{
+			name: "EscapeHex",
+			src:  `"\x41\xc3\xa9"`,
+			want: parens.String("Aé"),
+		},
+		{
+			name: "EscapeOctal",
+			src:  `"\101\000"`,
+			want: parens.String("A\x00"),
+		},
+		{
+			name:    "ShortUnicode",
+			src:     `"\u12"`,
+			wantErr: true,
+		},
+		{
+			name:    "InvalidUnicode",
+			src:     `"\U00110000"`,
+			wantErr: true,
+		},
+		{
+			name:    "SurrogateUnicode",
+			src:     `"\ud800"`,
+			wantErr: true,
+		},
+		{
+			name:    "InvalidOctal",
+			src:     `"\400"`,
+			wantErr: true,
+		},
+		{
+			name:    "UnicodeEOF",
+			src:     `"\u00`,
+			wantErr: true,
+		},
 	})
 }
 
+func TestReader_One_String_EscapeErrorPos(t *testing.T) {
+	rd := New(strings.NewReader("(foo\n  \"ab\\u12x4\")"))
+
+	_, err := rd.One()
+	if !errors.Is(err, ErrInvalidEscape) {
+		t.Fatalf("expected ErrInvalidEscape, got %#v", err)
+	}
+
+	e := err.(Error)
+	if want := (Position{File: "<string>", Ln: 2, Col: 6}); e.Begin != want {
+		t.Errorf("expected error to begin at %v, got %v", want, e.Begin)
+	}
+	if want := (Position{File: "<string>", Ln: 2, Col: 9}); e.End != want {
+		t.Errorf("expected error to end at %v, got %v", want, e.End)
+	}
+	if want := `\u12`; e.Form != want {
+		t.Errorf("expected form '%s', got '%s'", want, e.Form)
+	}
+}
+
+func TestReader_One_String_EscapeErrorForm(t *testing.T) {
+	table := []struct {
+		src     string
+		form    string
+		message string
+		end     Position
+	}{
+		{
+			src:     `"\u12"`,
+			form:    `\u12`,
+			message: `invalid escape sequence '\u12'`,
+			end:     Position{File: "<string>", Ln: 1, Col: 5},
+		},
+		{
+			src:     `"\u12G4"`,
+			form:    `\u12`,
+			message: `invalid escape sequence '\u12'`,
+			end:     Position{File: "<string>", Ln: 1, Col: 5},
+		},
+		{
+			src:     `"\x"`,
+			form:    `\x`,
+			message: `invalid escape sequence '\x'`,
+			end:     Position{File: "<string>", Ln: 1, Col: 3},
+		},
+		{
+			src:     `"\18"`,
+			form:    `\1`,
+			message: `invalid escape sequence '\1'`,
+			end:     Position{File: "<string>", Ln: 1, Col: 3},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := New(strings.NewReader(tt.src)).One()
+			if !errors.Is(err, ErrInvalidEscape) {
+				t.Fatalf("expected ErrInvalidEscape, got %#v", err)
+			}
+
+			e := err.(Error)
+			if e.Form != tt.form {
+				t.Errorf("expected form '%s', got '%s'", tt.form, e.Form)
+			}
+			if e.End != tt.end {
+				t.Errorf("expected error to end at %v, got %v", tt.end, e.End)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("expected error to contain %q, got %q", tt.message, err.Error())
+			}
+		})
+	}
+}
+
+func TestReader_One_String_RoundTrip(t *testing.T) {
+	for _, want := range []parens.String{
+		"hello",
+		"quote \" and \\ backslash",
+		"tab\tnew\nline\r\f\a\b\v",
+		"λ and \U0001F600",
+		"nul \x00 del \x7f",
+		"invalid \xff utf8",
+	} {
+		src, err := want.SExpr()
+		if err != nil {
+			t.Fatalf("SExpr() unexpected error: %v", err)
+		}
+
+		got, err := New(strings.NewReader(src)).One()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", src, err)
+		}
+
+		if got != want {
+			t.Errorf("read %s = %q, want %q", src, got, want)
+		}
+	}
+}
+
 func TestReader_One_Keyword(t *testing.T) {
 	executeReaderTests(t, []readerTestCase{
 		{
@@ -585,6 +876,449 @@ func TestReader_One_List(t *testing.T) {
 	})
 }
 
+func TestReader_One_Vector(t *testing.T) {
+	executeReaderTests(t, []readerTestCase{
+		{
+			name: "EmptyVector",
+			src:  `[]`,
+			want: parens.NewVector(),
+		},
+		{
+			name: "VectorWithMultipleEntry",
+			src:  `[+ 0xF 3.1413]`,
+			want: parens.NewVector(
+				parens.Symbol("+"),
+				parens.Int64(15),
+				parens.Float64(3.1413),
+			),
+		},
+		{
+			name: "NestedForms",
+			src:  `[a [b] (c)]`,
+			want: parens.NewVector(
+				parens.Symbol("a"),
+				parens.NewVector(parens.Symbol("b")),
+				parens.NewList(parens.Symbol("c")),
+			),
+		},
+		{
+			name: "SymbolBeforeDelimiter",
+			src:  `[a,b]`,
+			want: parens.NewVector(parens.Symbol("a"), parens.Symbol("b")),
+		},
+		{
+			name:    "UnexpectedEOF",
+			src:     "[1 2",
+			wantErr: true,
+		},
+		{
+			name:    "UnmatchedDelimiter",
+			src:     "]",
+			wantErr: true,
+		},
+	})
+}
+
+func TestReader_One_Map(t *testing.T) {
+	executeReaderTests(t, []readerTestCase{
+		{
+			name: "EmptyMap",
+			src:  `{}`,
+			want: &parens.Map{},
+		},
+		{
+			name: "MapWithEntries",
+			src:  `{:a 1, "b" [c]}`,
+			want: mustMap(
+				parens.Keyword("a"), parens.Int64(1),
+				parens.String("b"), parens.NewVector(parens.Symbol("c")),
+			),
+		},
+		{
+			name: "NestedMap",
+			src:  `{:a {:b (c)}}`,
+			want: mustMap(
+				parens.Keyword("a"), mustMap(parens.Keyword("b"), parens.NewList(parens.Symbol("c"))),
+			),
+		},
+		{
+			name: "CollectionKeys",
+			src:  `{(a) 1, [b] 2, #{c} 3}`,
+			want: mustMap(
+				parens.NewList(parens.Symbol("a")), parens.Int64(1),
+				parens.NewVector(parens.Symbol("b")), parens.Int64(2),
+				mustSet(parens.Symbol("c")), parens.Int64(3),
+			),
+		},
+		{
+			name:    "OddNumberOfForms",
+			src:     `{:a 1 :b}`,
+			wantErr: true,
+		},
+		{
+			name:    "DuplicateKey",
+			src:     `{:a 1 :a 2}`,
+			wantErr: true,
+		},
+		{
+			name:    "UnexpectedEOF",
+			src:     `{:a 1`,
+			wantErr: true,
+		},
+	})
+}
+
+func TestReader_One_Map_DuplicateKey(t *testing.T) {
+	_, err := New(strings.NewReader("{[1 2] 1\n [1 2] 2}")).One()
+	if !errors.Is(err, parens.ErrDuplicateKey) {
+		t.Fatalf("expected ErrDuplicateKey, got %#v", err)
+	}
+
+	e := err.(Error)
+	if want := (Position{File: "<string>", Ln: 2, Col: 2}); e.Begin != want {
+		t.Errorf("expected error to begin at %v, got %v", want, e.Begin)
+	}
+	if want := "duplicate key: [1 2]"; !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error to contain '%s', got '%s'", want, err)
+	}
+}
+
+func TestReader_One_Set_DuplicateItem(t *testing.T) {
+	_, err := New(strings.NewReader("#{[1 2]\n [1 2]}")).One()
+	if !errors.Is(err, parens.ErrDuplicateKey) {
+		t.Fatalf("expected ErrDuplicateKey, got %#v", err)
+	}
+
+	e := err.(Error)
+	if want := (Position{File: "<string>", Ln: 2, Col: 2}); e.Begin != want {
+		t.Errorf("expected error to begin at %v, got %v", want, e.Begin)
+	}
+	if want := "duplicate key: [1 2]"; !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error to contain '%s', got '%s'", want, err)
+	}
+}
+
+func mustMap(kvs ...parens.Any) *parens.Map {
+	m, err := parens.NewMap(kvs...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func TestReader_One_Set(t *testing.T) {
+	executeReaderTests(t, []readerTestCase{
+		{
+			name: "EmptySet",
+			src:  `#{}`,
+			want: mustSet(),
+		},
+		{
+			name: "SetWithItems",
+			src:  `#{:a 1 "b"}`,
+			want: mustSet(parens.Keyword("a"), parens.Int64(1), parens.String("b")),
+		},
+		{
+			name: "CollectionItems",
+			src:  `#{(a) [a]}`,
+			want: mustSet(parens.NewList(parens.Symbol("a")), parens.NewVector(parens.Symbol("a"))),
+		},
+		{
+			name:    "DuplicateItem",
+			src:     `#{:a :a}`,
+			wantErr: true,
+		},
+		{
+			name:    "UnexpectedEOF",
+			src:     `#{:a`,
+			wantErr: true,
+		},
+	})
+}
+
+func mustSet(items ...parens.Any) *parens.Set {
+	set, err := parens.NewSet(items...)
+	if err != nil {
+		panic(err)
+	}
+	return set
+}
+
+func TestReader_One_Regex(t *testing.T) {
+	executeReaderTests(t, []readerTestCase{
+		{
+			name: "Simple",
+			src:  `#"[a-z]+\d*"`,
+			want: mustRegex(`[a-z]+\d*`),
+		},
+		{
+			name: "EscapedQuote",
+			src:  `#"say \"hi\""`,
+			want: mustRegex(`say \"hi\"`),
+		},
+		{
+			name: "EscapedBackslash",
+			src:  `#"a\\"`,
+			want: mustRegex(`a\\`),
+		},
+		{
+			name:    "InvalidRegex",
+			src:     `#"(a"`,
+			wantErr: true,
+		},
+		{
+			name:    "UnexpectedEOF",
+			src:     `#"abc`,
+			wantErr: true,
+		},
+	})
+}
+
+func TestReader_One_Regex_Error(t *testing.T) {
+	_, err := New(strings.NewReader(`#"[a"`)).One()
+	if !errors.Is(err, ErrInvalidRegex) {
+		t.Errorf("expected ErrInvalidRegex, got %#v", err)
+	}
+}
+
+func TestReader_One_FnLiteral(t *testing.T) {
+	sym := func(s string) parens.Symbol { return parens.Symbol(s) }
+	fnForm := func(params []parens.Any, body ...parens.Any) parens.Any {
+		return parens.NewList(sym("fn"), parens.NewVector(params...), parens.NewList(body...))
+	}
+
+	executeReaderTests(t, []readerTestCase{
+		{
+			name: "NoArgs",
+			src:  `#(foo)`,
+			want: fnForm(nil, sym("foo")),
+		},
+		{
+			name: "ImplicitFirstArg",
+			src:  `#(> % 10)`,
+			want: fnForm([]parens.Any{sym("%1")}, sym(">"), sym("%1"), parens.Int64(10)),
+		},
+		{
+			name: "HighestArg",
+			src:  `#(foo %3 %)`,
+			want: fnForm([]parens.Any{sym("%1"), sym("%2"), sym("%3")}, sym("foo"), sym("%3"), sym("%1")),
+		},
+		{
+			name: "RestArgs",
+			src:  `#(foo %1 %&)`,
+			want: fnForm([]parens.Any{sym("%1"), sym("&"), sym("%&")}, sym("foo"), sym("%1"), sym("%&")),
+		},
+		{
+			name: "NestedForms",
+			src:  `#(foo [%2] (bar %1))`,
+			want: fnForm([]parens.Any{sym("%1"), sym("%2")},
+				sym("foo"), parens.NewVector(sym("%2")), parens.NewList(sym("bar"), sym("%1"))),
+		},
+		{
+			name: "NotArgLiteral",
+			src:  `#(foo %bar)`,
+			want: fnForm(nil, sym("foo"), sym("%bar")),
+		},
+		{
+			name:    "ZeroArg",
+			src:     `#(foo %0)`,
+			wantErr: true,
+		},
+		{
+			name:    "UnexpectedEOF",
+			src:     `#(foo %`,
+			wantErr: true,
+		},
+	})
+}
+
+func TestReader_One_FnLiteral_Nested(t *testing.T) {
+	rd := New(strings.NewReader("(map #(foo\n  #(bar %)) xs)"))
+
+	_, err := rd.One()
+	if !errors.Is(err, ErrNestedFnLiteral) {
+		t.Fatalf("expected ErrNestedFnLiteral, got %#v", err)
+	}
+
+	want := Position{File: "<string>", Ln: 2, Col: 4}
+	if got := err.(Error).Begin; got != want {
+		t.Errorf("expected error at %v, got %v", want, got)
+	}
+}
+
+func mustRegex(pattern string) parens.Regex {
+	re, err := parens.NewRegex(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
+func TestReader_One_Discard(t *testing.T) {
+	executeReaderTests(t, []readerTestCase{
+		{
+			name: "InList",
+			src:  `(foo #_ bar baz)`,
+			want: parens.NewList(parens.Symbol("foo"), parens.Symbol("baz")),
+		},
+		{
+			name: "InVector",
+			src:  `[1 #_ 2 #| 3 |# 4]`,
+			want: parens.NewVector(parens.Int64(1), parens.Int64(4)),
+		},
+		{
+			name: "InSet",
+			src:  `#{a_b #_ :c}`,
+			want: mustSet(parens.Symbol("a_b")),
+		},
+		{
+			// discarded args still count towards the arity, as in Clojure.
+			name: "InFnLiteral",
+			src:  `#(foo #_ %2 a_b)`,
+			want: parens.NewList(parens.Symbol("fn"), parens.NewVector(parens.Symbol("%1"), parens.Symbol("%2")),
+				parens.NewList(parens.Symbol("foo"), parens.Symbol("a_b"))),
+		},
+		{
+			name:    "BeforeClosingDelimiter",
+			src:     `(foo #_)`,
+			wantErr: true,
+		},
+	})
+}
+
+func TestReader_One_Spans(t *testing.T) {
+	rd := New(strings.NewReader("(foo\n  (bar 1))\n  'baz"))
+
+	form, err := rd.One()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pos := func(ln, col int) Position { return Position{File: "<string>", Ln: ln, Col: col} }
+
+	outer := form.(*parens.LinkedList)
+	assertSpan(t, parens.Span{Begin: pos(1, 1), End: pos(2, 10)}, outer.Span())
+
+	span, _ := outer.ItemSpan(0)
+	assertSpan(t, parens.Span{Begin: pos(1, 2), End: pos(1, 4)}, span)
+
+	span, _ = outer.ItemSpan(1)
+	assertSpan(t, parens.Span{Begin: pos(2, 3), End: pos(2, 9)}, span)
+
+	if _, found := outer.ItemSpan(2); found {
+		t.Errorf("expected no span for out of range item")
+	}
+
+	inner, _ := outer.Next()
+	first, _ := inner.First()
+	assertSpan(t, parens.Span{Begin: pos(2, 3), End: pos(2, 9)}, first.(*parens.LinkedList).Span())
+
+	// position must be tracked across multi-line containers.
+	form, err = rd.One()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSpan(t, parens.Span{Begin: pos(3, 3), End: pos(3, 6)}, form.(*parens.LinkedList).Span())
+
+	form, err = New(strings.NewReader("[1\n  foo]")).One()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vec := form.(*parens.Vector)
+	assertSpan(t, parens.Span{Begin: pos(1, 1), End: pos(2, 6)}, vec.Span())
+
+	span, _ = vec.ItemSpan(1)
+	assertSpan(t, parens.Span{Begin: pos(2, 3), End: pos(2, 5)}, span)
+}
+
+func TestReader_Spans(t *testing.T) {
+	pos := func(ln, col int) Position { return Position{File: "<string>", Ln: ln, Col: col} }
+	want := []parens.Span{
+		{Begin: pos(1, 1), End: pos(1, 3)},
+		{Begin: pos(2, 3), End: pos(2, 10)},
+		{Begin: pos(3, 1), End: pos(3, 4)},
+	}
+
+	src := "foo\n  ^:a 'bar ; comment\n:baz"
+
+	rd := New(strings.NewReader(src))
+	form, err := rd.One()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if form != parens.Symbol("foo") {
+		t.Errorf("expected plain symbol, got %#v", form)
+	}
+	if got := rd.Spans(); !reflect.DeepEqual(got, want[:1]) {
+		t.Errorf("Spans() after One() got = %+v, want = %+v", got, want[:1])
+	}
+
+	rd = New(strings.NewReader(src))
+	if _, err := rd.All(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := rd.Spans(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Spans() after All() got = %+v, want = %+v", got, want)
+	}
+}
+
+func assertSpan(t *testing.T, want, got parens.Span) {
+	t.Helper()
+	if want != got {
+		t.Errorf("span got = %+v, want = %+v", got, want)
+	}
+}
+
+// withoutSpans returns the form with source spans removed from all the lists
+// so that it can be compared with forms constructed in tests. Spans are tested
+// in TestReader_One_Spans. Metadata of the forms is retained.
+func withoutSpans(form parens.Any) parens.Any {
+	if meta := parens.Meta(form); meta != nil {
+		stripped := withoutSpans(form.(parens.WithMeta).WithMeta(nil))
+		return stripped.(parens.WithMeta).WithMeta(meta)
+	}
+
+	var items []parens.Any
+	collect := func(seq parens.Seq) {
+		_ = parens.ForEach(seq, func(item parens.Any) (bool, error) {
+			items = append(items, withoutSpans(item))
+			return false, nil
+		})
+	}
+
+	switch f := form.(type) {
+	case *parens.LinkedList:
+		if f == nil {
+			return form
+		}
+		collect(f)
+		return parens.NewList(items...)
+
+	case *parens.Vector:
+		collect(f)
+		return parens.NewVector(items...)
+
+	case *parens.Map:
+		m := &parens.Map{}
+		_ = parens.ForEach(f, func(entry parens.Any) (bool, error) {
+			kv := entry.(*parens.Vector)
+			key, _ := kv.Nth(0)
+			val, _ := kv.Nth(1)
+			m, _ = m.Assoc(withoutSpans(key), withoutSpans(val))
+			return false, nil
+		})
+		return m
+
+	case *parens.Set:
+		collect(f)
+		set, _ := parens.NewSet(items...)
+		return set
+	}
+
+	return form
+}
+
 type readerTestCase struct {
 	name    string
 	src     string
@@ -602,9 +1336,83 @@ func executeReaderTests(t *testing.T, tests []readerTestCase) {
 				t.Errorf("One() error = %#v, wantErr %#v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
+			if got = withoutSpans(got); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("One() got = %#v, want %#v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestReader_One_Meta(t *testing.T) {
+	sym := parens.Symbol("foo")
+	withMeta := func(form parens.Any, kvs ...parens.Any) parens.Any {
+		return form.(parens.WithMeta).WithMeta(mustMap(kvs...))
+	}
+
+	executeReaderTests(t, []readerTestCase{
+		{
+			name: "Keyword",
+			src:  `^:private foo`,
+			want: withMeta(sym, parens.Keyword("private"), parens.Bool(true)),
+		},
+		{
+			name: "Map",
+			src:  `^{:doc "hello"} foo`,
+			want: withMeta(sym, parens.Keyword("doc"), parens.String("hello")),
+		},
+		{
+			name: "Tag",
+			src:  `^String foo`,
+			want: withMeta(sym, parens.Keyword("tag"), parens.Symbol("String")),
+		},
+		{
+			name: "StringTag",
+			src:  `^"T" foo`,
+			want: withMeta(sym, parens.Keyword("tag"), parens.String("T")),
+		},
+		{
+			name: "Merged",
+			src:  `^:a ^{:b 1, :a false} foo`,
+			want: withMeta(sym, parens.Keyword("b"), parens.Int64(1), parens.Keyword("a"), parens.Bool(true)),
+		},
+		{
+			name: "List",
+			src:  `^:a (foo)`,
+			want: withMeta(parens.NewList(sym), parens.Keyword("a"), parens.Bool(true)),
+		},
+		{
+			name: "Vector",
+			src:  `^:a [1]`,
+			want: withMeta(parens.NewVector(parens.Int64(1)), parens.Keyword("a"), parens.Bool(true)),
+		},
+		{
+			name: "InList",
+			src:  `(def ^:private foo 1)`,
+			want: parens.NewList(
+				parens.Symbol("def"),
+				withMeta(sym, parens.Keyword("private"), parens.Bool(true)),
+				parens.Int64(1),
+			),
+		},
+		{
+			name: "SkipsComments",
+			src:  "^ ; comment\n :a #_ bar foo",
+			want: withMeta(sym, parens.Keyword("a"), parens.Bool(true)),
+		},
+		{
+			name:    "InvalidMeta",
+			src:     `^1 foo`,
+			wantErr: true,
+		},
+		{
+			name:    "NoMetaSupport",
+			src:     `^:a 1`,
+			wantErr: true,
+		},
+		{
+			name:    "EOF",
+			src:     `^:a`,
+			wantErr: true,
+		},
+	})
+}
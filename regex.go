@@ -0,0 +1,176 @@
+package parens
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var (
+	_ Any      = Regex{}
+	_ Hashable = Regex{}
+)
+
+// Regex represents a compiled regular expression Value. Regex values are
+// created by the #"pattern" reader macro and use the Go regexp syntax.
+type Regex struct {
+	re *regexp.Regexp
+
+	// anchored matches the entire string only. Used by re-matches.
+	anchored *regexp.Regexp
+}
+
+// NewRegex compiles the pattern and returns a Regex.
+func NewRegex(pattern string) (Regex, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Regex{}, err
+	}
+
+	anchored, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		return Regex{}, err
+	}
+	return Regex{re: re, anchored: anchored}, nil
+}
+
+// Regexp returns the underlying compiled regular expression.
+func (re Regex) Regexp() *regexp.Regexp { return re.regexp() }
+
+// SExpr returns a valid s-expression representing Regex.
+func (re Regex) SExpr() (string, error) { return re.String(), nil }
+
+// Equals returns true if 'other' is a regex with the same pattern.
+func (re Regex) Equals(other Any) (bool, error) {
+	o, ok := other.(Regex)
+	return ok && o.regexp().String() == re.regexp().String(), nil
+}
+
+// Hash returns the hash of the pattern.
+func (re Regex) Hash() (uint64, error) { return hashString(hashTagRegex, re.regexp().String()), nil }
+
+func (re Regex) String() string {
+	// quotes that are not already escaped must be escaped so that the result
+	// can be read back.
+	var b strings.Builder
+	b.WriteString(`#"`)
+	escaped := false
+	for _, r := range re.regexp().String() {
+		if r == '"' && !escaped {
+			b.WriteRune('\\')
+		}
+		escaped = r == '\\' && !escaped
+		b.WriteRune(r)
+	}
+	b.WriteRune('"')
+	return b.String()
+}
+
+func (re Regex) regexp() *regexp.Regexp {
+	if re.re == nil {
+		return regexp.MustCompile("")
+	}
+	return re.re
+}
+
+func (re Regex) anchoredRegexp() *regexp.Regexp {
+	if re.anchored == nil {
+		return regexp.MustCompile(`^$`)
+	}
+	return re.anchored
+}
+
+// reFindFn implements (re-find re s). Returns the first match of re in s or
+// nil if there is none. If re has groups, the match is returned as a vector
+// of the whole match followed by the groups.
+func reFindFn(_ *Env, args ...Any) (Any, error) {
+	re, s, err := regexArgs("re-find", args)
+	if err != nil {
+		return nil, err
+	}
+	return matchResult(re.FindStringSubmatchIndex(s), s, re.NumSubexp() > 0), nil
+}
+
+// reMatchesFn implements (re-matches re s). Same as re-find, except that the
+// match must span the entire string.
+func reMatchesFn(_ *Env, args ...Any) (Any, error) {
+	re, s, err := regexArgs("re-matches", args)
+	if err != nil {
+		return nil, err
+	}
+
+	anchored := args[0].(Regex).anchoredRegexp()
+	return matchResult(anchored.FindStringSubmatchIndex(s), s, re.NumSubexp() > 0), nil
+}
+
+// reSeqFn implements (re-seq re s). Returns a list of all the successive
+// matches of re in s, each in the form returned by re-find.
+func reSeqFn(_ *Env, args ...Any) (Any, error) {
+	re, s, err := regexArgs("re-seq", args)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Any
+	for _, loc := range re.FindAllStringSubmatchIndex(s, -1) {
+		matches = append(matches, matchResult(loc, s, re.NumSubexp() > 0))
+	}
+	return NewList(matches...), nil
+}
+
+// reGroupsFn implements (re-groups re s). Returns the first match of re in s
+// as a vector of the whole match followed by the groups irrespective of the
+// number of groups, or nil if there is no match.
+func reGroupsFn(_ *Env, args ...Any) (Any, error) {
+	re, s, err := regexArgs("re-groups", args)
+	if err != nil {
+		return nil, err
+	}
+	return matchResult(re.FindStringSubmatchIndex(s), s, true), nil
+}
+
+// matchResult converts the submatch indices to the match value. Groups that
+// did not participate in the match are nil.
+func matchResult(loc []int, s string, groups bool) Any {
+	if loc == nil {
+		return Nil{}
+	} else if !groups {
+		return String(s[loc[0]:loc[1]])
+	}
+
+	items := make([]Any, len(loc)/2)
+	for i := range items {
+		if loc[2*i] < 0 {
+			items[i] = Nil{}
+		} else {
+			items[i] = String(s[loc[2*i]:loc[2*i+1]])
+		}
+	}
+	return NewVector(items...)
+}
+
+func regexArgs(name string, args []Any) (*regexp.Regexp, string, error) {
+	if err := checkArity(name, args, 2); err != nil {
+		return nil, "", err
+	}
+
+	re, ok := args[0].(Regex)
+	if !ok {
+		return nil, "", Error{
+			Cause:   errors.New("not a regex"),
+			Message: fmt.Sprintf("%s: first argument must be regex, not '%s'", name, reflect.TypeOf(args[0])),
+		}
+	}
+
+	s, ok := args[1].(String)
+	if !ok {
+		return nil, "", Error{
+			Cause:   errors.New("not a string"),
+			Message: fmt.Sprintf("%s: second argument must be string, not '%s'", name, reflect.TypeOf(args[1])),
+		}
+	}
+
+	return re.regexp(), string(s), nil
+}
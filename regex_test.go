@@ -0,0 +1,89 @@
+package parens_test
+
+import (
+	"testing"
+
+	"github.com/spy16/parens"
+)
+
+func TestRegexFns(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{title: "Find", src: `(re-find #"\d+" "abc123def45")`, want: `"123"`},
+		{title: "FindGroups", src: `(re-find #"(\w)(\d)?" "a-b2")`, want: `["a" "a" nil]`},
+		{title: "FindNone", src: `(re-find #"\d+" "abc")`, want: "nil"},
+		{title: "Matches", src: `(re-matches #"a|ab" "ab")`, want: `"ab"`},
+		{title: "MatchesPartial", src: `(re-matches #"\d+" "123a")`, want: "nil"},
+		{title: "MatchesGroups", src: `(re-matches #"(\w+)@(\w+)" "me@host")`, want: `["me@host" "me" "host"]`},
+		{title: "Seq", src: `(re-seq #"\d" "a1b2c3")`, want: `("1" "2" "3")`},
+		{title: "SeqGroups", src: `(re-seq #"(\w)=(\d)" "a=1,b=2")`, want: `(["a=1" "a" "1"] ["b=2" "b" "2"])`},
+		{title: "SeqNone", src: `(re-seq #"\d" "abc")`, want: "()"},
+		{title: "Groups", src: `(re-groups #"\d+" "ab12")`, want: `["12"]`},
+		{title: "GroupsNone", src: `(re-groups #"\d+" "ab")`, want: "nil"},
+		{title: "Literal", src: `#"a\"b"`, want: `#"a\"b"`},
+		{title: "NotRegex", src: `(re-find "a" "a")`, wantErr: true},
+		{title: "NotString", src: `(re-find #"a" :a)`, wantErr: true},
+		{title: "Arity", src: `(re-find #"a")`, wantErr: true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := evalString(parens.New(), tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %#v", err)
+			} else if tt.wantErr {
+				return
+			}
+
+			s, err := toString(got)
+			requireNoErr(t, err)
+			assertEqual(t, tt.want, s)
+		})
+	}
+}
+
+func TestRegex_Equals(t *testing.T) {
+	t.Parallel()
+
+	a, err := parens.NewRegex(`\d+`)
+	requireNoErr(t, err)
+
+	b, err := parens.NewRegex(`\d+`)
+	requireNoErr(t, err)
+
+	c, err := parens.NewRegex(`\d*`)
+	requireNoErr(t, err)
+
+	eq, err := parens.Eq(a, b)
+	requireNoErr(t, err)
+	assertEqual(t, true, eq)
+
+	eq, err = parens.Eq(a, c)
+	requireNoErr(t, err)
+	assertEqual(t, false, eq)
+
+	ha, err := parens.Hash(a)
+	requireNoErr(t, err)
+
+	hb, err := parens.Hash(b)
+	requireNoErr(t, err)
+	assertEqual(t, ha, hb)
+
+	s, err := parens.Regex{}.SExpr()
+	requireNoErr(t, err)
+	assertEqual(t, `#""`, s)
+}
+
+func TestNewRegex_Invalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := parens.NewRegex(`(`); err == nil {
+		t.Errorf("expected error for invalid pattern")
+	}
+}
@@ -57,7 +57,7 @@ func (repl *REPL) Loop(ctx context.Context) error {
 	repl.setPrompt(false)
 
 	for ctx.Err() == nil {
-		err := repl.readEvalPrint()
+		err := repl.readEvalPrint(ctx)
 		if err != nil {
 			if err == io.EOF {
 				return nil
@@ -71,8 +71,9 @@ func (repl *REPL) Loop(ctx context.Context) error {
 }
 
 // readEval reads one form from the input, evaluates it and prints the result.
-func (repl *REPL) readEvalPrint() error {
-	forms, err := repl.read()
+// Evaluation is aborted if the context is cancelled.
+func (repl *REPL) readEvalPrint(ctx context.Context) error {
+	forms, spans, err := repl.read()
 	if err != nil {
 		switch err.(type) {
 		case reader.Error:
@@ -86,15 +87,14 @@ func (repl *REPL) readEvalPrint() error {
 		return nil
 	}
 
-	res, err := parens.EvalAll(repl.rootEnv, forms)
-	if err != nil {
-		return repl.print(err)
-	}
-	if len(res) == 0 {
-		return repl.print(nil)
+	var res parens.Any
+	for i, form := range forms {
+		if res, err = repl.rootEnv.EvalContext(ctx, form); err != nil {
+			return repl.print(parens.WithPos(err, spans[i].Begin))
+		}
 	}
 
-	return repl.print(res[len(res)-1])
+	return repl.print(res)
 }
 
 func (repl *REPL) Write(b []byte) (int, error) {
@@ -109,7 +109,8 @@ func (repl *REPL) print(v interface{}) error {
 	return repl.printer.Fprintln(repl.output, v)
 }
 
-func (repl *REPL) read() ([]parens.Any, error) {
+// read returns the forms read from the input along with their source spans.
+func (repl *REPL) read() ([]parens.Any, []parens.Span, error) {
 	var src string
 	lineNo := 1
 
@@ -119,13 +120,13 @@ func (repl *REPL) read() ([]parens.Any, error) {
 		line, err := repl.input.Readline()
 		err = repl.mapInputErr(err)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		src += line + "\n"
 
 		if strings.TrimSpace(src) == "" {
-			return nil, nil
+			return nil, nil, nil
 		}
 
 		rd := repl.factory.NewReader(strings.NewReader(src))
@@ -138,10 +139,10 @@ func (repl *REPL) read() ([]parens.Any, error) {
 				continue
 			}
 
-			return nil, err
+			return nil, nil, err
 		}
 
-		return form, nil
+		return form, rd.Spans(), nil
 	}
 }
 
@@ -0,0 +1,213 @@
+package parens
+
+import (
+	"strings"
+)
+
+var (
+	_ Any              = (*Set)(nil)
+	_ Seq              = (*Set)(nil)
+	_ SExpressable     = (*Set)(nil)
+	_ EqualityProvider = (*Set)(nil)
+	_ Hashable         = (*Set)(nil)
+	_ Positional       = (*Set)(nil)
+)
+
+// Set is an immutable, persistent hash set. Items must implement Hashable.
+// Set is backed by a Map and all the updates return a new set sharing
+// structure with the original. As a Seq, items are in an unspecified but
+// stable order. Zero value is an empty set ready for use.
+type Set struct {
+	items *Map
+	meta  *Map
+	span  Span
+}
+
+// NewSet returns a new set containing given values. Duplicate values are
+// added only once.
+func NewSet(items ...Any) (*Set, error) {
+	s, err := (&Set{}).Conj(items...)
+	if err != nil {
+		return nil, err
+	}
+	return s.(*Set), nil
+}
+
+// NewPositionalSet is same as NewSet but annotates the set with the source
+// span of the set literal it was read from.
+func NewPositionalSet(span Span, items ...Any) (*Set, error) {
+	set, err := NewSet(items...)
+	if err != nil {
+		return nil, err
+	}
+	set.span = span
+	return set, nil
+}
+
+// Span returns the source span of the set. Sets that are not read from source
+// or are derived from another set have no span.
+func (set *Set) Span() Span {
+	if set == nil {
+		return Span{}
+	}
+	return set.span
+}
+
+// Size returns the number of items in the set.
+func (set *Set) Size() int {
+	if set == nil {
+		return 0
+	}
+	return set.items.Size()
+}
+
+// Contains returns true if the value is a member of the set.
+func (set *Set) Contains(v Any) (bool, error) {
+	if set == nil {
+		return false, nil
+	}
+	return set.items.HasKey(v)
+}
+
+// Conj returns a new set with the items added.
+func (set *Set) Conj(items ...Any) (Seq, error) {
+	m := &Map{}
+	if set != nil && set.items != nil {
+		m = set.items
+	}
+
+	for _, item := range items {
+		var err error
+		if m, err = m.Assoc(item, item); err != nil {
+			return nil, err
+		}
+	}
+	return &Set{items: m, meta: set.Meta()}, nil
+}
+
+// Disj returns a new set with the items removed.
+func (set *Set) Disj(items ...Any) (*Set, error) {
+	var m *Map
+	if set != nil {
+		m = set.items
+	}
+
+	for _, item := range items {
+		var err error
+		if m, err = m.Dissoc(item); err != nil {
+			return nil, err
+		}
+	}
+	return &Set{items: m, meta: set.Meta()}, nil
+}
+
+// Meta returns the metadata of the set.
+func (set *Set) Meta() *Map {
+	if set == nil {
+		return nil
+	}
+	return set.meta
+}
+
+// WithMeta returns a copy of the set with the given metadata.
+func (set *Set) WithMeta(meta *Map) Any {
+	res := &Set{}
+	if set != nil {
+		*res = *set
+	}
+	res.meta = meta
+	return res
+}
+
+// SExpr returns a valid s-expression for Set.
+func (set *Set) SExpr() (string, error) {
+	var b strings.Builder
+	b.WriteString("#{")
+
+	first := true
+	err := set.each(func(item Any) error {
+		if !first {
+			b.WriteString(" ")
+		}
+		first = false
+
+		s, err := toSExpr(item)
+		b.WriteString(s)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+// Equals returns true if the other value is also a Set and contains the same
+// items irrespective of the order in which they were added.
+func (set *Set) Equals(other Any) (bool, error) {
+	o, ok := other.(*Set)
+	if !ok || o.Size() != set.Size() {
+		return false, nil
+	}
+
+	err := set.each(func(item Any) error {
+		found, err := o.Contains(item)
+		if err == nil && !found {
+			return errStopIteration
+		}
+		return err
+	})
+
+	if err == errStopIteration {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Hash returns a hash computed from the items of the set. The hash does not
+// depend on the order of the items.
+func (set *Set) Hash() (uint64, error) {
+	var sum uint64
+	err := set.each(func(item Any) error {
+		h, err := Hash(item)
+		sum += h
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return hashUint64(hashTagSet, sum), nil
+}
+
+// Count returns the number of items in the set.
+func (set *Set) Count() (int, error) { return set.Size(), nil }
+
+// First returns an item of the set. Returns nil if the set is empty.
+func (set *Set) First() (Any, error) {
+	if set.Size() == 0 {
+		return nil, nil
+	}
+
+	entry, err := set.items.First()
+	if err != nil {
+		return nil, err
+	}
+	return entry.(*Vector).nth(0), nil
+}
+
+// Next returns a sequence of all the items of the set except the one returned
+// by First.
+func (set *Set) Next() (Seq, error) {
+	if set.Size() == 0 {
+		return nil, nil
+	}
+	return newMapSeq(set.items, true).Next()
+}
+
+func (set *Set) each(f func(item Any) error) error {
+	if set == nil {
+		return nil
+	}
+	return set.items.each(func(key, _ Any) error { return f(key) })
+}
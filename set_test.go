@@ -0,0 +1,180 @@
+package parens_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spy16/parens"
+)
+
+func TestSet_Conj(t *testing.T) {
+	t.Parallel()
+
+	set, err := parens.NewSet(parens.Keyword("a"), parens.Int64(1), parens.Keyword("a"))
+	requireNoErr(t, err)
+	assertEqual(t, 2, set.Size())
+
+	res, err := set.Conj(parens.String("b"))
+	requireNoErr(t, err)
+	updated := res.(*parens.Set)
+	assertEqual(t, 3, updated.Size())
+	assertEqual(t, 2, set.Size())
+
+	for _, tt := range []struct {
+		item parens.Any
+		want bool
+	}{
+		{item: parens.Keyword("a"), want: true},
+		{item: parens.Int64(1), want: true},
+		{item: parens.String("b"), want: true},
+		{item: parens.String("a")},
+	} {
+		found, err := updated.Contains(tt.item)
+		requireNoErr(t, err)
+		if found != tt.want {
+			t.Errorf("Contains(%v) = %t, want %t", tt.item, found, tt.want)
+		}
+	}
+
+	_, err = set.Conj(struct{}{})
+	if !errors.Is(err, parens.ErrNotHashable) {
+		t.Errorf("expected ErrNotHashable, got %#v", err)
+	}
+}
+
+func TestSet_Disj(t *testing.T) {
+	t.Parallel()
+
+	set, err := parens.NewSet(parens.Int64(1), parens.Int64(2), parens.Int64(3))
+	requireNoErr(t, err)
+
+	res, err := set.Disj(parens.Int64(2), parens.Int64(4))
+	requireNoErr(t, err)
+	assertEqual(t, 2, res.Size())
+	assertEqual(t, 3, set.Size())
+
+	found, err := res.Contains(parens.Int64(2))
+	requireNoErr(t, err)
+	assertEqual(t, false, found)
+}
+
+func TestSet_Equals(t *testing.T) {
+	t.Parallel()
+
+	a, err := parens.NewSet(parens.Int64(1), parens.Keyword("b"), parens.String("c"))
+	requireNoErr(t, err)
+
+	for _, tt := range []struct {
+		desc  string
+		other parens.Any
+		want  bool
+	}{
+		{
+			desc:  "different insertion order",
+			other: mustSet(t, parens.String("c"), parens.Int64(1), parens.Keyword("b")),
+			want:  true,
+		},
+		{
+			desc:  "different items",
+			other: mustSet(t, parens.String("c"), parens.Int64(2), parens.Keyword("b")),
+		},
+		{
+			desc:  "subset",
+			other: mustSet(t, parens.Int64(1), parens.Keyword("b")),
+		},
+		{
+			desc:  "vector with same items",
+			other: parens.NewVector(parens.Int64(1), parens.Keyword("b"), parens.String("c")),
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := parens.Eq(a, tt.other)
+			requireNoErr(t, err)
+			assertEqual(t, tt.want, got)
+		})
+	}
+}
+
+func TestSet_Seq(t *testing.T) {
+	t.Parallel()
+
+	set := mustSet(t, parens.Int64(1), parens.Int64(2), parens.Int64(3))
+
+	var sum parens.Int64
+	err := parens.ForEach(set, func(item parens.Any) (bool, error) {
+		sum += item.(parens.Int64)
+		return false, nil
+	})
+	requireNoErr(t, err)
+	assertEqual(t, parens.Int64(6), sum)
+
+	s, err := mustSet(t, parens.Keyword("a")).SExpr()
+	requireNoErr(t, err)
+	assertEqual(t, "#{:a}", s)
+
+	s, err = (&parens.Set{}).SExpr()
+	requireNoErr(t, err)
+	assertEqual(t, "#{}", s)
+}
+
+func TestSet_Seq_Walk(t *testing.T) {
+	t.Parallel()
+
+	var items []parens.Any
+	for i := 0; i < 500; i++ {
+		items = append(items, parens.Int64(i))
+	}
+	set := mustSet(t, items...)
+
+	var sum parens.Int64
+	var seq parens.Seq = set
+	for n := set.Size(); n > 0; n-- {
+		cnt, err := seq.Count()
+		requireNoErr(t, err)
+		assertEqual(t, n, cnt)
+
+		item, err := seq.First()
+		requireNoErr(t, err)
+		sum += item.(parens.Int64)
+
+		seq, err = seq.Next()
+		requireNoErr(t, err)
+	}
+	assertEqual(t, parens.Int64(500*499/2), sum)
+
+	item, err := seq.First()
+	requireNoErr(t, err)
+	assertEqual(t, nil, item)
+}
+
+func TestSet_Eval(t *testing.T) {
+	t.Parallel()
+
+	got, err := evalString(parens.New(), `(def x 1) #{x :b "x"}`)
+	requireNoErr(t, err)
+
+	want := mustSet(t, parens.Int64(1), parens.Keyword("b"), parens.String("x"))
+	eq, err := parens.Eq(want, got)
+	requireNoErr(t, err)
+	if !eq {
+		t.Errorf("Eval() got = %v, want = %v", got, want)
+	}
+
+	_, err = evalString(parens.New(), `(def x :a) #{x :a}`)
+	if !errors.Is(err, parens.ErrDuplicateKey) {
+		t.Errorf("expected ErrDuplicateKey, got %#v", err)
+	}
+
+	_, err = evalString(parens.New(), `(def x [1 2]) #{x [1 2]}`)
+	if err == nil || !strings.Contains(err.Error(), "[1 2]") {
+		t.Errorf("expected duplicate key error with '[1 2]', got %v", err)
+	}
+}
+
+func mustSet(t *testing.T, items ...parens.Any) *parens.Set {
+	t.Helper()
+	set, err := parens.NewSet(items...)
+	requireNoErr(t, err)
+	return set
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync/atomic"
 )
 
 var (
@@ -12,10 +13,15 @@ var (
 	_ = ParseSpecial(parseGoExpr)
 	_ = ParseSpecial(parseDefExpr)
 	_ = ParseSpecial(parseQuoteExpr)
+	_ = ParseSpecial(parseFnExpr)
+	_ = ParseSpecial(parseLetExpr)
+	_ = ParseSpecial(parseDefMacroExpr)
+	_ = ParseSpecial(parseSyntaxQuoteExpr)
+	_ = ParseSpecial(parseLazySeqExpr)
 )
 
 func parseDoExpr(env *Env, args Seq) (Expr, error) {
-	var de DoExpr
+	de := DoExpr{Env: env}
 	err := ForEach(args, func(item Any) (bool, error) {
 		expr, err := env.Analyze(item)
 		if err != nil {
@@ -84,6 +90,27 @@ func parseQuoteExpr(_ *Env, args Seq) (Expr, error) {
 	}, nil
 }
 
+func parseSyntaxQuoteExpr(env *Env, args Seq) (Expr, error) {
+	if count, err := args.Count(); err != nil {
+		return nil, err
+	} else if count != 1 {
+		return nil, Error{
+			Cause:   errors.New("invalid syntax-quote form"),
+			Message: fmt.Sprintf("requires exactly 1 argument, got %d", count),
+		}
+	}
+
+	first, err := args.First()
+	if err != nil {
+		return nil, err
+	}
+
+	return SyntaxQuoteExpr{
+		Env:  env,
+		Form: first,
+	}, nil
+}
+
 func parseDefExpr(env *Env, args Seq) (Expr, error) {
 	if count, err := args.Count(); err != nil {
 		return nil, err
@@ -99,7 +126,7 @@ func parseDefExpr(env *Env, args Seq) (Expr, error) {
 		return nil, err
 	}
 
-	sym, ok := first.(Symbol)
+	sym, ok := toSymbol(first)
 	if !ok {
 		return nil, Error{
 			Cause:   errors.New("invalid def form"),
@@ -126,6 +153,7 @@ func parseDefExpr(env *Env, args Seq) (Expr, error) {
 		Env:   env,
 		Name:  string(sym),
 		Value: res,
+		Meta:  Meta(first),
 	}, nil
 }
 
@@ -143,3 +171,250 @@ func parseGoExpr(env *Env, args Seq) (Expr, error) {
 
 	return GoExpr{Env: env, Value: v}, nil
 }
+
+func parseFnExpr(env *Env, args Seq) (Expr, error) {
+	forms, err := toSlice(args)
+	if err != nil {
+		return nil, err
+	}
+
+	fe := FnExpr{Env: env}
+	if len(forms) > 0 {
+		if sym, ok := toSymbol(forms[0]); ok {
+			fe.Name = string(sym)
+			forms = forms[1:]
+		}
+	}
+
+	if len(forms) == 0 {
+		return nil, Error{
+			Cause:   errors.New("invalid fn form"),
+			Message: "parameter list is required",
+		}
+	}
+
+	if !isMultiArity(forms[0]) {
+		// single arity form: (fn name? (params*) body*)
+		m, err := parseFnMethod(forms)
+		if err != nil {
+			return nil, err
+		}
+		fe.Methods = []FnMethod{m}
+		return fe, nil
+	}
+
+	// multi-arity form: (fn name? ((params*) body*)+)
+	arities := map[int]bool{}
+	variadic := false
+	for _, form := range forms {
+		seq, ok := form.(Seq)
+		if !ok {
+			return nil, Error{
+				Cause:   errors.New("invalid fn form"),
+				Message: fmt.Sprintf("arity must be a sequence, not '%s'", reflect.TypeOf(form)),
+			}
+		}
+
+		items, err := toSlice(seq)
+		if err != nil {
+			return nil, err
+		}
+
+		m, err := parseFnMethod(items)
+		if err != nil {
+			return nil, err
+		}
+
+		if m.Variadic() {
+			if variadic {
+				return nil, Error{
+					Cause:   errors.New("invalid fn form"),
+					Message: "cannot have more than one variadic arity",
+				}
+			}
+			variadic = true
+		} else if arities[len(m.Params)] {
+			return nil, Error{
+				Cause:   errors.New("invalid fn form"),
+				Message: fmt.Sprintf("cannot have two arities with %d parameter(s)", len(m.Params)),
+			}
+		}
+		arities[len(m.Params)] = true
+
+		fe.Methods = append(fe.Methods, m)
+	}
+
+	return fe, nil
+}
+
+// parseFnMethod parses a single arity of the form ((params*) body*).
+func parseFnMethod(forms []Any) (FnMethod, error) {
+	var m FnMethod
+	if len(forms) == 0 {
+		return m, Error{
+			Cause:   errors.New("invalid fn form"),
+			Message: "parameter list is required",
+		}
+	}
+
+	params, ok := forms[0].(Seq)
+	if !ok {
+		return m, Error{
+			Cause:   errors.New("invalid fn form"),
+			Message: fmt.Sprintf("parameter list must be a sequence, not '%s'", reflect.TypeOf(forms[0])),
+		}
+	}
+
+	names, err := toSlice(params)
+	if err != nil {
+		return m, err
+	}
+
+	for i := 0; i < len(names); i++ {
+		sym, ok := toSymbol(names[i])
+		if !ok {
+			return m, Error{
+				Cause:   errors.New("invalid fn form"),
+				Message: fmt.Sprintf("parameter must be symbol, not '%s'", reflect.TypeOf(names[i])),
+			}
+		}
+
+		if sym != "&" {
+			m.Params = append(m.Params, string(sym))
+			continue
+		}
+
+		var rest Symbol
+		if i == len(names)-2 {
+			rest, _ = toSymbol(names[i+1])
+		}
+		if rest == "" || rest == "&" {
+			return m, Error{
+				Cause:   errors.New("invalid fn form"),
+				Message: "'&' must be followed by exactly one symbol",
+			}
+		}
+		m.Rest = string(rest)
+		break
+	}
+
+	m.Body = forms[1:]
+	m.analyzed = &atomic.Value{}
+	return m, nil
+}
+
+// isMultiArity returns true if the form following the optional name in a fn
+// form is a list of arities instead of a parameter list.
+func isMultiArity(form Any) bool {
+	seq, ok := form.(Seq)
+	if !ok {
+		return false
+	}
+
+	first, err := seq.First()
+	if err != nil {
+		return false
+	}
+
+	_, isSeq := first.(Seq)
+	return isSeq
+}
+
+func parseLetExpr(env *Env, args Seq) (Expr, error) {
+	forms, err := toSlice(args)
+	if err != nil {
+		return nil, err
+	} else if len(forms) == 0 {
+		return nil, Error{
+			Cause:   errors.New("invalid let form"),
+			Message: "binding list is required",
+		}
+	}
+
+	bindings, ok := forms[0].(Seq)
+	if !ok {
+		return nil, Error{
+			Cause:   errors.New("invalid let form"),
+			Message: fmt.Sprintf("binding list must be a sequence, not '%s'", reflect.TypeOf(forms[0])),
+		}
+	}
+
+	pairs, err := toSlice(bindings)
+	if err != nil {
+		return nil, err
+	} else if len(pairs)%2 != 0 {
+		return nil, Error{
+			Cause:   errors.New("invalid let form"),
+			Message: "binding list requires an even number of forms",
+		}
+	}
+
+	// the names are bound (to nil) while analyzing the values and the body
+	// so that they resolve and shadow the globals as they do when evaluated.
+	prev := env.scope
+	defer func() { env.scope = prev }()
+
+	le := LetExpr{Env: env}
+	for i := 0; i < len(pairs); i += 2 {
+		sym, ok := toSymbol(pairs[i])
+		if !ok {
+			return nil, Error{
+				Cause:   errors.New("invalid let form"),
+				Message: fmt.Sprintf("binding name must be symbol, not '%s'", reflect.TypeOf(pairs[i])),
+			}
+		}
+
+		val, err := env.expandAnalyze(pairs[i+1])
+		if err != nil {
+			return nil, err
+		}
+		le.Names = append(le.Names, string(sym))
+		le.Values = append(le.Values, val)
+
+		s := newScope(env.scope)
+		s.bind(string(sym), Nil{})
+		env.scope = s
+	}
+
+	if le.Body, err = analyzeBody(env, forms[1:]); err != nil {
+		return nil, err
+	}
+	return le, nil
+}
+
+func parseDefMacroExpr(env *Env, args Seq) (Expr, error) {
+	first, err := args.First()
+	if err != nil {
+		return nil, err
+	}
+
+	sym, ok := toSymbol(first)
+	if !ok {
+		return nil, Error{
+			Cause:   errors.New("invalid defmacro form"),
+			Message: fmt.Sprintf("first arg must be symbol, not '%s'", reflect.TypeOf(first)),
+		}
+	}
+
+	expr, err := parseFnExpr(env, args)
+	if err != nil {
+		return nil, err
+	}
+
+	fe := expr.(FnExpr)
+	fe.Macro = true
+
+	return &DefExpr{
+		Env:   env,
+		Name:  string(sym),
+		Value: fe,
+	}, nil
+}
+
+func parseLazySeqExpr(env *Env, args Seq) (Expr, error) {
+	body, err := toSlice(args)
+	if err != nil {
+		return nil, err
+	}
+	return LazySeqExpr{Env: env, Body: body}, nil
+}
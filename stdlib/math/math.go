@@ -0,0 +1,370 @@
+// Package math provides arithmetic and comparison functions for parens. The
+// functions are opt-in and can be registered as globals:
+//
+//	env := parens.New(parens.WithGlobals(math.Globals(), nil))
+//
+// Arithmetic follows the numeric tower defined by parens.Number. Int64
+// results that overflow are promoted to BigInt and ratios that reduce to a
+// whole number are returned as integers.
+package math
+
+import (
+	"errors"
+	"fmt"
+	stdmath "math"
+	"math/big"
+	"reflect"
+
+	"github.com/spy16/parens"
+)
+
+var (
+	// ErrNotNumber is returned when an arithmetic function is invoked with a
+	// value that is not a parens.Number.
+	ErrNotNumber = errors.New("not a number")
+
+	// ErrDivideByZero is returned when dividing by zero.
+	ErrDivideByZero = errors.New("divide by zero")
+)
+
+// Globals returns the arithmetic and comparison functions keyed by their names.
+func Globals() map[string]parens.Any {
+	return map[string]parens.Any{
+		"+":    parens.GoFunc(add),
+		"-":    parens.GoFunc(sub),
+		"*":    parens.GoFunc(mul),
+		"/":    parens.GoFunc(div),
+		"quot": parens.GoFunc(quot),
+		"rem":  parens.GoFunc(rem),
+		"mod":  parens.GoFunc(mod),
+		"inc":  parens.GoFunc(inc),
+		"dec":  parens.GoFunc(dec),
+		"=":    compareFn("=", parens.Eq),
+		"not=": parens.GoFunc(notEq),
+		"<":    compareFn("<", parens.Lt),
+		">":    compareFn(">", parens.Gt),
+		"<=":   compareFn("<=", parens.Le),
+		">=":   compareFn(">=", parens.Ge),
+	}
+}
+
+func add(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	return fold("+", parens.Int64(0), args, Add)
+}
+
+func sub(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	if len(args) == 0 {
+		return nil, arityErr("-", 0)
+	} else if len(args) == 1 {
+		return fold("-", parens.Int64(0), args, Sub)
+	}
+	return fold("-", nil, args, Sub)
+}
+
+func mul(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	return fold("*", parens.Int64(1), args, Mul)
+}
+
+func div(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	if len(args) == 0 {
+		return nil, arityErr("/", 0)
+	} else if len(args) == 1 {
+		return fold("/", parens.Int64(1), args, Div)
+	}
+	return fold("/", nil, args, Div)
+}
+
+func quot(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	return binary("quot", args, Quot)
+}
+
+func rem(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	return binary("rem", args, Rem)
+}
+
+func mod(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	return binary("mod", args, Mod)
+}
+
+func inc(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	if len(args) != 1 {
+		return nil, arityErr("inc", len(args))
+	}
+	return binary("inc", []parens.Any{args[0], parens.Int64(1)}, Add)
+}
+
+func dec(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	if len(args) != 1 {
+		return nil, arityErr("dec", len(args))
+	}
+	return binary("dec", []parens.Any{args[0], parens.Int64(1)}, Sub)
+}
+
+func notEq(env *parens.Env, args ...parens.Any) (parens.Any, error) {
+	eq, err := compareFn("not=", parens.Eq)(env, args...)
+	if err != nil {
+		return nil, err
+	}
+	return !eq.(parens.Bool), nil
+}
+
+// compareFn returns a function that returns true if cmp holds for every pair
+// of consecutive arguments.
+func compareFn(name string, cmp func(a, b parens.Any) (bool, error)) parens.GoFunc {
+	return func(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+		if len(args) == 0 {
+			return nil, arityErr(name, 0)
+		}
+
+		for i := 1; i < len(args); i++ {
+			ok, err := cmp(args[i-1], args[i])
+			if err != nil {
+				return nil, parens.Error{
+					Cause: err,
+					Message: fmt.Sprintf("%s: cannot compare '%s' and '%s'",
+						name, reflect.TypeOf(args[i-1]), reflect.TypeOf(args[i])),
+				}
+			} else if !ok {
+				return parens.Bool(false), nil
+			}
+		}
+
+		return parens.Bool(true), nil
+	}
+}
+
+// fold applies op to the arguments from left to right. If init is not nil,
+// it is used as the first operand.
+func fold(name string, init parens.Any, args []parens.Any, op func(a, b parens.Number) (parens.Number, error)) (parens.Any, error) {
+	if init != nil {
+		args = append([]parens.Any{init}, args...)
+	}
+
+	nums, err := toNumbers(name, args)
+	if err != nil {
+		return nil, err
+	}
+
+	res := nums[0]
+	for _, n := range nums[1:] {
+		if res, err = op(res, n); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func binary(name string, args []parens.Any, op func(a, b parens.Number) (parens.Number, error)) (parens.Any, error) {
+	if len(args) != 2 {
+		return nil, arityErr(name, len(args))
+	}
+	return fold(name, nil, args, op)
+}
+
+func toNumbers(name string, args []parens.Any) ([]parens.Number, error) {
+	nums := make([]parens.Number, len(args))
+	for i, arg := range args {
+		n, ok := arg.(parens.Number)
+		if !ok {
+			return nil, parens.Error{
+				Cause:   ErrNotNumber,
+				Message: fmt.Sprintf("%s: argument of type '%s'", name, reflect.TypeOf(arg)),
+			}
+		}
+		nums[i] = n
+	}
+	return nums, nil
+}
+
+func arityErr(name string, got int) error {
+	return parens.Error{
+		Cause:   parens.ErrArity,
+		Message: fmt.Sprintf("%d argument(s) passed to %s", got, name),
+	}
+}
+
+// Add returns a + b.
+func Add(a, b parens.Number) (parens.Number, error) {
+	return arith(a, b, addInt64, (*big.Int).Add, (*big.Rat).Add, func(x, y float64) float64 { return x + y })
+}
+
+// Sub returns a - b.
+func Sub(a, b parens.Number) (parens.Number, error) {
+	return arith(a, b, subInt64, (*big.Int).Sub, (*big.Rat).Sub, func(x, y float64) float64 { return x - y })
+}
+
+// Mul returns a * b.
+func Mul(a, b parens.Number) (parens.Number, error) {
+	return arith(a, b, mulInt64, (*big.Int).Mul, (*big.Rat).Mul, func(x, y float64) float64 { return x * y })
+}
+
+// Div returns a / b. Division of integers returns a Ratio unless b divides a.
+// Returns error with ErrDivideByZero cause if b is an exact zero.
+func Div(a, b parens.Number) (parens.Number, error) {
+	a, b = parens.Promote(a, b)
+	if f, ok := a.(parens.Float64); ok {
+		return f / b.(parens.Float64), nil
+	} else if isZero(b) {
+		return nil, divideByZero("/")
+	}
+
+	if x, ok := a.(parens.Int64); ok {
+		y := b.(parens.Int64)
+		if x%y == 0 && !(x == stdmath.MinInt64 && y == -1) {
+			return x / y, nil
+		}
+	}
+
+	res := new(big.Rat).Quo(toRat(a), toRat(b))
+	if _, isBig := a.(parens.BigInt); isBig && res.IsInt() {
+		return parens.NewBigInt(res.Num()), nil
+	}
+	return fromRat(res), nil
+}
+
+// Quot returns the quotient of a / b truncated towards zero.
+func Quot(a, b parens.Number) (parens.Number, error) {
+	q, _, err := quotRem("quot", a, b)
+	return q, err
+}
+
+// Rem returns the remainder of truncated division of a by b. The result has
+// the sign of a.
+func Rem(a, b parens.Number) (parens.Number, error) {
+	_, r, err := quotRem("rem", a, b)
+	return r, err
+}
+
+// Mod returns the modulus of floored division of a by b. The result has the
+// sign of b.
+func Mod(a, b parens.Number) (parens.Number, error) {
+	_, r, err := quotRem("mod", a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isZero(r) && (sign(r) < 0) != (sign(b) < 0) {
+		return Add(r, b)
+	}
+	return r, nil
+}
+
+func quotRem(name string, a, b parens.Number) (q, r parens.Number, err error) {
+	a, b = parens.Promote(a, b)
+	if isZero(b) {
+		return nil, nil, divideByZero(name)
+	}
+
+	switch x := a.(type) {
+	case parens.Int64:
+		y := b.(parens.Int64)
+		if x == stdmath.MinInt64 && y == -1 {
+			// quotient overflows int64.
+			return parens.NewBigInt(new(big.Int).Neg(big.NewInt(int64(x)))), parens.Int64(0), nil
+		}
+		return x / y, x % y, nil
+
+	case parens.BigInt:
+		bq, br := new(big.Int).QuoRem(x.Big(), b.(parens.BigInt).Big(), new(big.Int))
+		return parens.NewBigInt(bq), parens.NewBigInt(br), nil
+
+	case parens.Ratio:
+		xr, yr := x.Rat(), b.(parens.Ratio).Rat()
+		ratio := new(big.Rat).Quo(xr, yr)
+		bq := new(big.Int).Quo(ratio.Num(), ratio.Denom())
+		remainder := new(big.Rat).Sub(xr, new(big.Rat).Mul(yr, new(big.Rat).SetInt(bq)))
+		return fromRat(new(big.Rat).SetInt(bq)), fromRat(remainder), nil
+	}
+
+	x, y := float64(a.(parens.Float64)), float64(b.(parens.Float64))
+	return parens.Float64(stdmath.Trunc(x / y)), parens.Float64(stdmath.Mod(x, y)), nil
+}
+
+// arith performs the operation on the operands after promoting them to the
+// same type. int64 operation returns false on overflow in which case the
+// operation is performed on BigInt values.
+func arith(a, b parens.Number,
+	i64 func(x, y int64) (int64, bool),
+	bigInt func(z, x, y *big.Int) *big.Int,
+	rat func(z, x, y *big.Rat) *big.Rat,
+	f64 func(x, y float64) float64,
+) (parens.Number, error) {
+	a, b = parens.Promote(a, b)
+
+	switch x := a.(type) {
+	case parens.Int64:
+		y := b.(parens.Int64)
+		if res, ok := i64(int64(x), int64(y)); ok {
+			return parens.Int64(res), nil
+		}
+		return parens.NewBigInt(bigInt(new(big.Int), big.NewInt(int64(x)), big.NewInt(int64(y)))), nil
+
+	case parens.BigInt:
+		return parens.NewBigInt(bigInt(new(big.Int), x.Big(), b.(parens.BigInt).Big())), nil
+
+	case parens.Ratio:
+		return fromRat(rat(new(big.Rat), x.Rat(), b.(parens.Ratio).Rat())), nil
+
+	case parens.Float64:
+		return parens.Float64(f64(float64(x), float64(b.(parens.Float64)))), nil
+	}
+
+	return nil, parens.Error{
+		Cause:   ErrNotNumber,
+		Message: fmt.Sprintf("unsupported number type '%s'", reflect.TypeOf(a)),
+	}
+}
+
+func addInt64(x, y int64) (int64, bool) {
+	s := x + y
+	return s, (x^s)&(y^s) >= 0
+}
+
+func subInt64(x, y int64) (int64, bool) {
+	d := x - y
+	return d, (x^y)&(x^d) >= 0
+}
+
+func mulInt64(x, y int64) (int64, bool) {
+	if x == 0 || y == 0 {
+		return 0, true
+	}
+
+	p := x * y
+	if (x == -1 && y == stdmath.MinInt64) || (y == -1 && x == stdmath.MinInt64) {
+		return p, false
+	}
+	return p, p/y == x
+}
+
+// fromRat returns the rational as an integer if it is a whole number and as
+// a Ratio otherwise.
+func fromRat(r *big.Rat) parens.Number {
+	if !r.IsInt() {
+		return parens.NewRatio(r)
+	} else if r.Num().IsInt64() {
+		return parens.Int64(r.Num().Int64())
+	}
+	return parens.NewBigInt(r.Num())
+}
+
+func toRat(n parens.Number) *big.Rat {
+	switch v := n.(type) {
+	case parens.Int64:
+		return new(big.Rat).SetInt64(int64(v))
+	case parens.BigInt:
+		return new(big.Rat).SetInt(v.Big())
+	}
+	return n.(parens.Ratio).Rat()
+}
+
+func sign(n parens.Number) int {
+	c, _ := n.Comp(parens.Int64(0))
+	return c
+}
+
+func isZero(n parens.Number) bool { return sign(n) == 0 }
+
+func divideByZero(name string) error {
+	return parens.Error{Cause: ErrDivideByZero, Message: name}
+}
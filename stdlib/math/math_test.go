@@ -0,0 +1,140 @@
+package math_test
+
+import (
+	"errors"
+	stdmath "math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/spy16/parens"
+	"github.com/spy16/parens/reader"
+	"github.com/spy16/parens/stdlib/math"
+)
+
+func TestGlobals(t *testing.T) {
+	t.Parallel()
+
+	maxInt := new(big.Int).SetInt64(stdmath.MaxInt64)
+	minInt := new(big.Int).SetInt64(stdmath.MinInt64)
+
+	table := []struct {
+		desc    string
+		src     string
+		want    parens.Any
+		wantErr error
+	}{
+		{desc: "AddNoArgs", src: `(+)`, want: parens.Int64(0)},
+		{desc: "Add", src: `(+ 1 2 3)`, want: parens.Int64(6)},
+		{desc: "AddFloat", src: `(+ 1 0.5)`, want: parens.Float64(1.5)},
+		{desc: "AddRatio", src: `(+ 1/3 2/3)`, want: parens.Int64(1)},
+		{desc: "AddRatioInt", src: `(+ 1/2 1)`, want: parens.NewRatio(big.NewRat(3, 2))},
+		{
+			desc: "AddOverflow",
+			src:  `(+ 9223372036854775807 1)`,
+			want: parens.NewBigInt(new(big.Int).Add(maxInt, big.NewInt(1))),
+		},
+		{desc: "AddBigInt", src: `(+ 1N 2)`, want: parens.NewBigInt(big.NewInt(3))},
+		{desc: "Negate", src: `(- 5)`, want: parens.Int64(-5)},
+		{
+			desc: "NegateMinInt",
+			src:  `(- -9223372036854775808)`,
+			want: parens.NewBigInt(new(big.Int).Neg(minInt)),
+		},
+		{desc: "Sub", src: `(- 10 1 2)`, want: parens.Int64(7)},
+		{
+			desc: "SubOverflow",
+			src:  `(- -9223372036854775808 1)`,
+			want: parens.NewBigInt(new(big.Int).Sub(minInt, big.NewInt(1))),
+		},
+		{desc: "MulNoArgs", src: `(*)`, want: parens.Int64(1)},
+		{desc: "Mul", src: `(* 2 3 4)`, want: parens.Int64(24)},
+		{
+			desc: "MulOverflow",
+			src:  `(* 9223372036854775807 2)`,
+			want: parens.NewBigInt(new(big.Int).Mul(maxInt, big.NewInt(2))),
+		},
+		{
+			desc: "MulMinIntOverflow",
+			src:  `(* -9223372036854775808 -1)`,
+			want: parens.NewBigInt(new(big.Int).Neg(minInt)),
+		},
+		{desc: "Div", src: `(/ 12 2 3)`, want: parens.Int64(2)},
+		{desc: "DivRatio", src: `(/ 1 3)`, want: parens.NewRatio(big.NewRat(1, 3))},
+		{desc: "Reciprocal", src: `(/ 4)`, want: parens.NewRatio(big.NewRat(1, 4))},
+		{desc: "DivFloat", src: `(/ 1 2.0)`, want: parens.Float64(0.5)},
+		{desc: "DivFloatByZero", src: `(/ 1.0 0)`, want: parens.Float64(stdmath.Inf(1))},
+		{desc: "DivBigInt", src: `(/ 10N 5)`, want: parens.NewBigInt(big.NewInt(2))},
+		{desc: "DivByZero", src: `(/ 1 0)`, wantErr: math.ErrDivideByZero},
+		{desc: "DivNoArgs", src: `(/)`, wantErr: parens.ErrArity},
+		{desc: "Quot", src: `(quot -7 2)`, want: parens.Int64(-3)},
+		{desc: "QuotFloat", src: `(quot 7.5 2)`, want: parens.Float64(3)},
+		{desc: "QuotRatio", src: `(quot 7/2 1/2)`, want: parens.Int64(7)},
+		{desc: "Rem", src: `(rem -7 2)`, want: parens.Int64(-1)},
+		{desc: "RemByZero", src: `(rem 7 0)`, wantErr: math.ErrDivideByZero},
+		{desc: "Mod", src: `(mod -7 2)`, want: parens.Int64(1)},
+		{desc: "ModNegativeDivisor", src: `(mod 7 -2)`, want: parens.Int64(-1)},
+		{desc: "ModFloat", src: `(mod -7.5 2)`, want: parens.Float64(0.5)},
+		{desc: "ModArity", src: `(mod 7)`, wantErr: parens.ErrArity},
+		{desc: "Inc", src: `(inc 1)`, want: parens.Int64(2)},
+		{
+			desc: "IncOverflow",
+			src:  `(inc 9223372036854775807)`,
+			want: parens.NewBigInt(new(big.Int).Add(maxInt, big.NewInt(1))),
+		},
+		{desc: "Dec", src: `(dec 1/2)`, want: parens.NewRatio(big.NewRat(-1, 2))},
+		{desc: "NotNumber", src: `(+ 1 "a")`, wantErr: math.ErrNotNumber},
+		{desc: "Eq", src: `(= 1 1.0 1N)`, want: parens.Bool(true)},
+		{desc: "EqSingle", src: `(= :a)`, want: parens.Bool(true)},
+		{desc: "EqFalse", src: `(= 1 1 2)`, want: parens.Bool(false)},
+		{desc: "EqNonNumber", src: `(= :a :a)`, want: parens.Bool(true)},
+		{desc: "NotEq", src: `(not= 1 2)`, want: parens.Bool(true)},
+		{desc: "Lt", src: `(< 1 3/2 2.0 3N)`, want: parens.Bool(true)},
+		{desc: "LtFalse", src: `(< 1 3 2)`, want: parens.Bool(false)},
+		{desc: "Gt", src: `(> 3 2 1)`, want: parens.Bool(true)},
+		{desc: "Le", src: `(<= 1 1 2)`, want: parens.Bool(true)},
+		{desc: "Ge", src: `(>= 2 2 3)`, want: parens.Bool(false)},
+		{desc: "LtNoArgs", src: `(<)`, wantErr: parens.ErrArity},
+		{desc: "LtIncomparable", src: `(< 1 :a)`, wantErr: parens.ErrIncomparableTypes},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			env := parens.New(parens.WithGlobals(math.Globals(), nil))
+
+			form, err := reader.New(strings.NewReader(tt.src)).One()
+			if err != nil {
+				t.Fatalf("failed to read '%s': %v", tt.src, err)
+			}
+
+			got, err := env.Eval(form)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Eval() error = %#v, want %#v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Eval() unexpected error: %#v", err)
+			}
+
+			eq, err := parens.Eq(got, tt.want)
+			if err != nil || !eq || typeName(got) != typeName(tt.want) {
+				t.Errorf("Eval() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func typeName(v parens.Any) string {
+	switch v.(type) {
+	case parens.Int64:
+		return "Int64"
+	case parens.BigInt:
+		return "BigInt"
+	case parens.Ratio:
+		return "Ratio"
+	case parens.Float64:
+		return "Float64"
+	}
+	return "other"
+}
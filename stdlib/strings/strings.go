@@ -0,0 +1,367 @@
+// Package strings provides string manipulation functions for parens. The
+// functions are opt-in and can be registered as globals:
+//
+//	env := parens.New(parens.WithGlobals(strings.Globals(), nil))
+//
+// Indices and lengths are in characters (runes), not bytes. Since String is
+// a Seq of Char, the core sequence functions (count, first, map etc.) work on
+// strings as well.
+package strings
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	stdstrings "strings"
+	"unicode"
+
+	"github.com/spy16/parens"
+)
+
+// ErrNotString is returned when a string function is invoked with a value
+// that is not a parens.String where one is required.
+var ErrNotString = errors.New("not a string")
+
+// Globals returns the string functions keyed by their names.
+func Globals() map[string]parens.Any {
+	return map[string]parens.Any{
+		"str":          parens.GoFunc(str),
+		"subs":         parens.GoFunc(subs),
+		"split":        parens.GoFunc(split),
+		"join":         parens.GoFunc(join),
+		"upper-case":   unary("upper-case", stdstrings.ToUpper),
+		"lower-case":   unary("lower-case", stdstrings.ToLower),
+		"trim":         unary("trim", stdstrings.TrimSpace),
+		"triml":        unary("triml", func(s string) string { return stdstrings.TrimLeftFunc(s, unicode.IsSpace) }),
+		"trimr":        unary("trimr", func(s string) string { return stdstrings.TrimRightFunc(s, unicode.IsSpace) }),
+		"replace":      parens.GoFunc(replace),
+		"blank?":       parens.GoFunc(blank),
+		"includes?":    predicate("includes?", stdstrings.Contains),
+		"starts-with?": predicate("starts-with?", stdstrings.HasPrefix),
+		"ends-with?":   predicate("ends-with?", stdstrings.HasSuffix),
+		"index-of":     parens.GoFunc(indexOf),
+		"matches?":     parens.GoFunc(matches),
+		"format":       parens.GoFunc(format),
+	}
+}
+
+// str implements (str & xs). Returns the concatenation of the values. nil is
+// rendered as empty string, strings and characters as is and other values as
+// s-expressions.
+func str(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	var b stdstrings.Builder
+	for _, arg := range args {
+		s, err := display(arg)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(s)
+	}
+	return parens.String(b.String()), nil
+}
+
+// subs implements (subs s start) and (subs s start end). Returns the
+// characters of s from start (inclusive) to end (exclusive, defaults to the
+// length of s).
+func subs(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return nil, arityErr("subs", len(args))
+	}
+
+	s, err := toString("subs", args[0])
+	if err != nil {
+		return nil, err
+	}
+	runes := []rune(s)
+
+	bounds := []int{0, len(runes)}
+	for i, arg := range args[1:] {
+		n, ok := arg.(parens.Int64)
+		if !ok {
+			return nil, parens.Error{
+				Cause:   errors.New("invalid subs"),
+				Message: fmt.Sprintf("index must be integer, not '%s'", reflect.TypeOf(arg)),
+			}
+		}
+		bounds[i] = int(n)
+	}
+
+	start, end := bounds[0], bounds[1]
+	if start < 0 || end > len(runes) || start > end {
+		return nil, parens.Error{
+			Cause:   parens.ErrIndexOutOfBounds,
+			Message: fmt.Sprintf("subs: [%d, %d) of string with %d characters", start, end, len(runes)),
+		}
+	}
+	return parens.String(runes[start:end]), nil
+}
+
+// split implements (split s sep). Returns a vector of the substrings of s
+// separated by sep. sep can be a string or a regex.
+func split(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	if len(args) != 2 {
+		return nil, arityErr("split", len(args))
+	}
+
+	s, err := toString("split", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []string
+	if re, ok := args[1].(parens.Regex); ok {
+		parts = re.Regexp().Split(s, -1)
+	} else {
+		sep, err := toString("split", args[1])
+		if err != nil {
+			return nil, err
+		}
+		parts = stdstrings.Split(s, sep)
+	}
+
+	items := make([]parens.Any, len(parts))
+	for i, p := range parts {
+		items[i] = parens.String(p)
+	}
+	return parens.NewVector(items...), nil
+}
+
+// join implements (join coll) and (join sep coll). Returns the items of coll
+// rendered as with str and separated by sep.
+func join(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, arityErr("join", len(args))
+	}
+
+	sep := ""
+	if len(args) == 2 {
+		s, err := toString("join", args[0])
+		if err != nil {
+			return nil, err
+		}
+		sep = s
+	}
+
+	coll := args[len(args)-1]
+	if parens.IsNil(coll) {
+		return parens.String(""), nil
+	}
+
+	seq, ok := coll.(parens.Seq)
+	if !ok {
+		return nil, parens.Error{
+			Cause:   parens.ErrNotSeq,
+			Message: fmt.Sprintf("join: value of type '%s'", reflect.TypeOf(coll)),
+		}
+	}
+
+	var parts []string
+	err := parens.ForEach(seq, func(item parens.Any) (bool, error) {
+		s, err := display(item)
+		parts = append(parts, s)
+		return err != nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parens.String(stdstrings.Join(parts, sep)), nil
+}
+
+// replace implements (replace s match replacement). Returns s with all the
+// occurrences of match replaced. match can be a string or a regex, in which
+// case replacement can refer to the groups using $1 etc.
+func replace(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	if len(args) != 3 {
+		return nil, arityErr("replace", len(args))
+	}
+
+	re, isRegex := args[1].(parens.Regex)
+	if isRegex {
+		args = []parens.Any{args[0], args[2]}
+	}
+
+	ss, err := toStrings("replace", args, len(args))
+	if err != nil {
+		return nil, err
+	} else if isRegex {
+		return parens.String(re.Regexp().ReplaceAllString(ss[0], ss[1])), nil
+	}
+	return parens.String(stdstrings.ReplaceAll(ss[0], ss[1], ss[2])), nil
+}
+
+// blank implements (blank? s). Returns true if s is nil, empty or contains
+// only whitespace.
+func blank(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	if len(args) != 1 {
+		return nil, arityErr("blank?", len(args))
+	} else if parens.IsNil(args[0]) {
+		return parens.Bool(true), nil
+	}
+
+	s, err := toString("blank?", args[0])
+	if err != nil {
+		return nil, err
+	}
+	return parens.Bool(stdstrings.TrimSpace(s) == ""), nil
+}
+
+// indexOf implements (index-of s substr). Returns the character index of the
+// first occurrence of substr in s or nil if not found.
+func indexOf(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	ss, err := toStrings("index-of", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	i := stdstrings.Index(ss[0], ss[1])
+	if i < 0 {
+		return parens.Nil{}, nil
+	}
+	return parens.Int64(len([]rune(ss[0][:i]))), nil
+}
+
+// matches implements (matches? s pattern). Returns true if s contains a match
+// of pattern, which can be a regex or a string containing a regex.
+func matches(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	if len(args) != 2 {
+		return nil, arityErr("matches?", len(args))
+	}
+
+	s, err := toString("matches?", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if re, ok := args[1].(parens.Regex); ok {
+		return parens.Bool(re.Regexp().MatchString(s)), nil
+	}
+
+	pattern, err := toString("matches?", args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, parens.Error{
+			Cause:   err,
+			Message: "matches?: invalid pattern",
+		}
+	}
+	return parens.Bool(re.MatchString(s)), nil
+}
+
+// format implements (format fmt & args). Returns the arguments formatted as
+// per fmt using Go fmt verbs. Arguments are converted to the corresponding Go
+// values (e.g., Int64 to int64) before formatting.
+func format(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+	if len(args) == 0 {
+		return nil, arityErr("format", 0)
+	}
+
+	f, err := toString("format", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]interface{}, len(args)-1)
+	for i, arg := range args[1:] {
+		if vals[i], err = toGo(arg); err != nil {
+			return nil, err
+		}
+	}
+	return parens.String(fmt.Sprintf(f, vals...)), nil
+}
+
+func unary(name string, fn func(s string) string) parens.GoFunc {
+	return func(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+		ss, err := toStrings(name, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return parens.String(fn(ss[0])), nil
+	}
+}
+
+func predicate(name string, fn func(s, substr string) bool) parens.GoFunc {
+	return func(_ *parens.Env, args ...parens.Any) (parens.Any, error) {
+		ss, err := toStrings(name, args, 2)
+		if err != nil {
+			return nil, err
+		}
+		return parens.Bool(fn(ss[0], ss[1])), nil
+	}
+}
+
+// toStrings validates that exactly n arguments are passed and all of them
+// are strings.
+func toStrings(name string, args []parens.Any, n int) ([]string, error) {
+	if len(args) != n {
+		return nil, arityErr(name, len(args))
+	}
+
+	ss := make([]string, n)
+	for i, arg := range args {
+		s, err := toString(name, arg)
+		if err != nil {
+			return nil, err
+		}
+		ss[i] = s
+	}
+	return ss, nil
+}
+
+func toString(name string, v parens.Any) (string, error) {
+	s, ok := v.(parens.String)
+	if !ok {
+		return "", parens.Error{
+			Cause:   ErrNotString,
+			Message: fmt.Sprintf("%s: argument of type '%s'", name, reflect.TypeOf(v)),
+		}
+	}
+	return string(s), nil
+}
+
+// display returns the value rendered as a string for use with str and join.
+func display(v parens.Any) (string, error) {
+	switch val := v.(type) {
+	case nil, parens.Nil:
+		return "", nil
+	case parens.String:
+		return string(val), nil
+	case parens.Char:
+		return string(rune(val)), nil
+	case parens.SExpressable:
+		return val.SExpr()
+	}
+	return fmt.Sprintf("%v", v), nil
+}
+
+// toGo returns the Go value corresponding to the value for use with format.
+func toGo(v parens.Any) (interface{}, error) {
+	switch val := v.(type) {
+	case parens.Int64:
+		return int64(val), nil
+	case parens.Float64:
+		return float64(val), nil
+	case parens.Bool:
+		return bool(val), nil
+	case parens.Char:
+		return rune(val), nil
+	case parens.String:
+		return string(val), nil
+	case parens.BigInt:
+		return val.Big(), nil
+	case parens.Ratio:
+		return val.Rat(), nil
+	}
+	return display(v)
+}
+
+func arityErr(name string, got int) error {
+	return parens.Error{
+		Cause:   parens.ErrArity,
+		Message: fmt.Sprintf("%d argument(s) passed to %s", got, name),
+	}
+}
@@ -0,0 +1,92 @@
+package strings_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spy16/parens"
+	"github.com/spy16/parens/reader"
+	pstrings "github.com/spy16/parens/stdlib/strings"
+)
+
+func TestGlobals(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		desc    string
+		src     string
+		want    parens.Any
+		wantErr error
+	}{
+		{desc: "Str", src: `(str "a" 1 \b :c nil [1 "x"])`, want: parens.String(`a1b:c[1 "x"]`)},
+		{desc: "StrNoArgs", src: `(str)`, want: parens.String("")},
+		{desc: "Subs", src: `(subs "héllo" 1 3)`, want: parens.String("él")},
+		{desc: "SubsToEnd", src: `(subs "héllo" 2)`, want: parens.String("llo")},
+		{desc: "SubsOutOfBounds", src: `(subs "abc" 2 5)`, wantErr: parens.ErrIndexOutOfBounds},
+		{desc: "SubsArity", src: `(subs "abc")`, wantErr: parens.ErrArity},
+		{
+			desc: "Split",
+			src:  `(split "a,b,c" ",")`,
+			want: parens.NewVector(parens.String("a"), parens.String("b"), parens.String("c")),
+		},
+		{
+			desc: "SplitRegex",
+			src:  `(split "a1b22c" #"\d+")`,
+			want: parens.NewVector(parens.String("a"), parens.String("b"), parens.String("c")),
+		},
+		{desc: "Join", src: `(join ", " [1 "a" \b])`, want: parens.String("1, a, b")},
+		{desc: "JoinNoSep", src: `(join '(1 2))`, want: parens.String("12")},
+		{desc: "JoinString", src: `(join "-" "abc")`, want: parens.String("a-b-c")},
+		{desc: "JoinNotSeq", src: `(join 1)`, wantErr: parens.ErrNotSeq},
+		{desc: "UpperCase", src: `(upper-case "héllo")`, want: parens.String("HÉLLO")},
+		{desc: "LowerCase", src: `(lower-case "ABC")`, want: parens.String("abc")},
+		{desc: "Trim", src: `(trim "  a b \t")`, want: parens.String("a b")},
+		{desc: "TrimL", src: `(triml "  a ")`, want: parens.String("a ")},
+		{desc: "TrimR", src: `(trimr "  a ")`, want: parens.String("  a")},
+		{desc: "Replace", src: `(replace "a-b-c" "-" "+")`, want: parens.String("a+b+c")},
+		{desc: "ReplaceRegex", src: `(replace "a=1 b=2" #"(\w)=(\d)" "$2:$1")`, want: parens.String("1:a 2:b")},
+		{desc: "Blank", src: `(blank? " \t")`, want: parens.Bool(true)},
+		{desc: "BlankNil", src: `(blank? nil)`, want: parens.Bool(true)},
+		{desc: "NotBlank", src: `(blank? " a")`, want: parens.Bool(false)},
+		{desc: "Includes", src: `(includes? "hello" "ell")`, want: parens.Bool(true)},
+		{desc: "StartsWith", src: `(starts-with? "hello" "he")`, want: parens.Bool(true)},
+		{desc: "EndsWith", src: `(ends-with? "hello" "he")`, want: parens.Bool(false)},
+		{desc: "IndexOf", src: `(index-of "héllo" "l")`, want: parens.Int64(2)},
+		{desc: "IndexOfNotFound", src: `(index-of "hello" "x")`, want: parens.Nil{}},
+		{desc: "Matches", src: `(matches? "abc123" "[0-9]+$")`, want: parens.Bool(true)},
+		{desc: "NotMatches", src: `(matches? "abc" "^[0-9]+$")`, want: parens.Bool(false)},
+		{desc: "MatchesRegex", src: `(matches? "abc123" #"^[a-z]+\d+$")`, want: parens.Bool(true)},
+		{desc: "MatchesInvalid", src: `(matches? "abc" "(")`, wantErr: errors.New("invalid pattern")},
+		{desc: "Format", src: `(format "%s=%d (%.1f) %c %v" "x" 10 1.25 \y :k)`, want: parens.String("x=10 (1.2) y :k")},
+		{desc: "FormatBigInt", src: `(format "%d" 100000000000000000000)`, want: parens.String("100000000000000000000")},
+		{desc: "NotString", src: `(upper-case 1)`, wantErr: pstrings.ErrNotString},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			globals := pstrings.Globals()
+			globals["nil"] = parens.Nil{}
+			env := parens.New(parens.WithGlobals(globals, nil))
+
+			form, err := reader.New(strings.NewReader(tt.src)).One()
+			if err != nil {
+				t.Fatalf("failed to read '%s': %v", tt.src, err)
+			}
+
+			got, err := env.Eval(form)
+			if tt.wantErr != nil {
+				if err == nil || !(errors.Is(err, tt.wantErr) || strings.Contains(err.Error(), tt.wantErr.Error())) {
+					t.Fatalf("Eval() error = %#v, want %#v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Eval() unexpected error: %#v", err)
+			}
+
+			if eq, err := parens.Eq(got, tt.want); err != nil || !eq {
+				t.Errorf("Eval() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
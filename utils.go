@@ -1,11 +1,15 @@
 package parens
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"sync/atomic"
 )
 
+var gensymCounter uint64
+
 // EvalAll evaluates each value in the list against the given env and returns
 // a list of resultant values.
 func EvalAll(env *Env, vals []Any) ([]Any, error) {
@@ -72,11 +76,16 @@ func SeqString(seq Seq, begin, end, sep string) (string, error) {
 
 // ForEach reads from the sequence and calls the given function for each item.
 // Function can return true to stop the iteration.
-func ForEach(seq Seq, call func(item Any) (bool, error)) (err error) {
+func ForEach(seq Seq, call func(item Any) (bool, error)) error {
+	return forEach(nil, seq, call)
+}
+
+// forEach is same as ForEach but realizes the lazy seqs on behalf of r.
+func forEach(r *realizer, seq Seq, call func(item Any) (bool, error)) (err error) {
 	var v Any
 	var done bool
 	for seq != nil {
-		if v, err = seq.First(); err != nil || v == nil {
+		if v, err = firstOf(r, seq); err != nil || v == nil {
 			break
 		}
 
@@ -84,10 +93,107 @@ func ForEach(seq Seq, call func(item Any) (bool, error)) (err error) {
 			break
 		}
 
-		if seq, err = seq.Next(); err != nil {
+		if seq, err = nextOf(r, seq); err != nil {
 			break
 		}
 	}
 
 	return
 }
+
+// gensym returns a new symbol unique within the process by inserting a
+// sequence number between prefix and suffix.
+func gensym(prefix, suffix string) Symbol {
+	id := atomic.AddUint64(&gensymCounter, 1)
+	return Symbol(fmt.Sprintf("%s%d%s", prefix, id, suffix))
+}
+
+// spanOf returns the source span of the form if it is Positional.
+func spanOf(form Any) Span {
+	if p, ok := form.(Positional); ok {
+		return p.Span()
+	}
+	return Span{}
+}
+
+// itemPos returns the source position of the i-th item of the sequence if
+// the sequence tracks positions of its items.
+func itemPos(seq Seq, i int) Position {
+	if ip, ok := seq.(interface{ ItemSpan(int) (Span, bool) }); ok {
+		if span, found := ip.ItemSpan(i); found {
+			return span.Begin
+		}
+	}
+	return Position{}
+}
+
+// formName returns the s-expression of the form if it is SExpressable and its
+// Go representation otherwise. Used for naming invocations in stack traces.
+func formName(form Any) string {
+	if sxpr, ok := form.(SExpressable); ok {
+		if s, err := sxpr.SExpr(); err == nil {
+			return s
+		}
+	}
+	return fmt.Sprintf("%v", form)
+}
+
+// isQualified returns true if the symbol name is of the form `ns/name`.
+func isQualified(sym string) bool {
+	i := strings.IndexRune(sym, '/')
+	return i > 0 && i < len(sym)-1
+}
+
+func toSlice(seq Seq) ([]Any, error) {
+	var items []Any
+	err := ForEach(seq, func(item Any) (bool, error) {
+		items = append(items, item)
+		return false, nil
+	})
+	return items, err
+}
+
+// evalBody evaluates the forms in order and returns the result of the last
+// form. Returns Nil{} if there are no forms. Evaluation is aborted if the
+// context of the env is done.
+func evalBody(env *Env, forms []Any) (Any, error) {
+	var res Any = Nil{}
+	for _, form := range forms {
+		if err := env.ctxErr(); err != nil {
+			return nil, err
+		}
+
+		v, err := env.Eval(form)
+		if err != nil {
+			return nil, err
+		}
+		res = v
+	}
+	return res, nil
+}
+
+// analyzeBody expands and analyzes each of the forms.
+func analyzeBody(env *Env, forms []Any) ([]Expr, error) {
+	exprs := make([]Expr, 0, len(forms))
+	for _, form := range forms {
+		expr, err := env.expandAnalyze(form)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, nil
+}
+
+// errStopIteration is used by internal iteration callbacks to stop the
+// iteration early without reporting an error.
+var errStopIteration = errors.New("stop iteration")
+
+// toSExpr returns the s-expression of the value if it is SExpressable and the
+// Go representation otherwise.
+func toSExpr(v Any) (string, error) {
+	if sxpr, ok := v.(SExpressable); ok {
+		return sxpr.SExpr()
+	}
+	return fmt.Sprintf("%#v", v), nil
+}
@@ -1,9 +1,13 @@
 package parens
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"reflect"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 var (
@@ -17,7 +21,20 @@ var (
 	_ Any = Keyword("specimen")
 	_ Any = (*LinkedList)(nil)
 
-	_ Seq = (*LinkedList)(nil)
+	_ Seq        = (*LinkedList)(nil)
+	_ Seq        = String("specimen")
+	_ Positional = (*LinkedList)(nil)
+
+	_ Hashable = Nil{}
+	_ Hashable = Int64(0)
+	_ Hashable = Float64(1.123123)
+	_ Hashable = Bool(true)
+	_ Hashable = Char('∂')
+	_ Hashable = String("specimen")
+	_ Hashable = Symbol("specimen")
+	_ Hashable = MetaSymbol{Symbol: "specimen"}
+	_ Hashable = Keyword("specimen")
+	_ Hashable = (*LinkedList)(nil)
 )
 
 // Comparable values define a partial ordering.
@@ -72,28 +89,29 @@ func Gt(a, b Any) (bool, error) {
 	return false, ErrIncomparableTypes
 }
 
-// Le returns true if a <= b
+// Le returns true if a <= b. Returns false if either of the values is NaN.
 func Le(a, b Any) (bool, error) {
 	if acmp, ok := a.(Comparable); ok {
 		i, err := acmp.Comp(b)
-		return i <= 0, err
+		return i <= 0 && !unordered(a, b), err
 	}
 
 	return false, ErrIncomparableTypes
 }
 
-// Ge returns true if a >= b
+// Ge returns true if a >= b. Returns false if either of the values is NaN.
 func Ge(a, b Any) (bool, error) {
 	if acmp, ok := a.(Comparable); ok {
 		i, err := acmp.Comp(b)
-		return i >= 0, err
+		return i >= 0 && !unordered(a, b), err
 	}
 
 	return false, ErrIncomparableTypes
 }
 
 // Cons returns a new seq with `v` added as the first and `seq` as the rest.
-// seq can be nil as well.
+// seq can be nil as well. If seq is lazy, it is not realized and the count
+// of the new seq is computed when requested.
 func Cons(v Any, seq Seq) (Seq, error) {
 	newSeq := &LinkedList{
 		first: v,
@@ -101,7 +119,9 @@ func Cons(v Any, seq Seq) (Seq, error) {
 		count: 1,
 	}
 
-	if seq != nil {
+	if isLazy(seq) {
+		newSeq.count = -1
+	} else if seq != nil {
 		cnt, err := seq.Count()
 		if err != nil {
 			return nil, err
@@ -129,6 +149,17 @@ func NewList(items ...Any) Seq {
 	return lst
 }
 
+// NewPositionalList returns a new linked-list containing given values and
+// annotated with the source span of the list and of each of the items. Item
+// spans are retrieved using ItemSpan(). Empty list carries no span.
+func NewPositionalList(span Span, items []Any, itemSpans []Span) Seq {
+	lst := NewList(items...)
+	if ll, ok := lst.(*LinkedList); ok && ll != nil {
+		ll.pos = &listPos{span: span, items: itemSpans}
+	}
+	return lst
+}
+
 // Nil represents the Value 'nil'.
 type Nil struct{}
 
@@ -140,63 +171,44 @@ func (Nil) Equals(other Any) (bool, error) { return IsNil(other), nil }
 
 func (Nil) String() string { return "nil" }
 
+// Hash returns the hash of nil.
+func (Nil) Hash() (uint64, error) { return hashUint64(hashTagNil, 0), nil }
+
 // Int64 represents a 64-bit integer Value.
 type Int64 int64
 
 // SExpr returns a valid s-expression representing Int64.
 func (i64 Int64) SExpr() (string, error) { return i64.String(), nil }
 
-// Equals returns true if 'other' is also an integer and has same Value.
+// Equals returns true if 'other' is a number with the same Value.
 func (i64 Int64) Equals(other Any) (bool, error) {
-	val, ok := other.(Int64)
-	return ok && (val == i64), nil
-}
-
-// Comp performs comparison against another Int64.
-func (i64 Int64) Comp(other Any) (int, error) {
-	if n, ok := other.(Int64); ok {
-		switch {
-		case i64 > n:
-			return 1, nil
-		case i64 < n:
-			return -1, nil
-		default:
-			return 0, nil
-		}
+	if val, ok := other.(Int64); ok {
+		return val == i64, nil
 	}
-
-	return 0, ErrIncomparableTypes
+	return numEq(i64, other), nil
 }
 
+// Comp performs comparison against another number.
+func (i64 Int64) Comp(other Any) (int, error) { return numComp(i64, other) }
+
 func (i64 Int64) String() string { return strconv.Itoa(int(i64)) }
 
+// Hash returns the hash of the number.
+func (i64 Int64) Hash() (uint64, error) { return hashNumber(i64), nil }
+
+func (i64 Int64) level() numLevel { return levelInt64 }
+
 // Float64 represents a 64-bit double precision floating point Value.
 type Float64 float64
 
 // SExpr returns a valid s-expression representing Float64.
 func (f64 Float64) SExpr() (string, error) { return f64.String(), nil }
 
-// Equals returns true if 'other' is also a float and has same Value.
-func (f64 Float64) Equals(other Any) (bool, error) {
-	val, ok := other.(Float64)
-	return ok && (val == f64), nil
-}
-
-// Comp performs comparison against another Float64.
-func (f64 Float64) Comp(other Any) (int, error) {
-	if n, ok := other.(Float64); ok {
-		switch {
-		case f64 > n:
-			return 1, nil
-		case f64 < n:
-			return -1, nil
-		default:
-			return 0, nil
-		}
-	}
+// Equals returns true if 'other' is a number with the same Value.
+func (f64 Float64) Equals(other Any) (bool, error) { return numEq(f64, other), nil }
 
-	return 0, ErrIncomparableTypes
-}
+// Comp performs comparison against another number.
+func (f64 Float64) Comp(other Any) (int, error) { return numComp(f64, other) }
 
 func (f64 Float64) String() string {
 	if math.Abs(float64(f64)) >= 1e16 {
@@ -205,6 +217,11 @@ func (f64 Float64) String() string {
 	return fmt.Sprintf("%f", f64)
 }
 
+// Hash returns the hash of the number.
+func (f64 Float64) Hash() (uint64, error) { return hashNumber(f64), nil }
+
+func (f64 Float64) level() numLevel { return levelFloat64 }
+
 // Bool represents a boolean Value.
 type Bool bool
 
@@ -224,6 +241,14 @@ func (b Bool) String() string {
 	return "false"
 }
 
+// Hash returns the hash of the boolean value.
+func (b Bool) Hash() (uint64, error) {
+	if b {
+		return hashUint64(hashTagBool, 1), nil
+	}
+	return hashUint64(hashTagBool, 0), nil
+}
+
 // Char represents a Unicode character.
 type Char rune
 
@@ -240,7 +265,10 @@ func (char Char) Equals(other Any) (bool, error) {
 
 func (char Char) String() string { return fmt.Sprintf("\\%c", char) }
 
-// String represents a string of characters.
+// Hash returns the hash of the character.
+func (char Char) Hash() (uint64, error) { return hashUint64(hashTagChar, uint64(char)), nil }
+
+// String represents a string of characters. String is a Seq of its Chars.
 type String string
 
 // SExpr returns a valid s-expression representing String.
@@ -252,7 +280,55 @@ func (str String) Equals(other Any) (bool, error) {
 	return isStr && (otherStr == str), nil
 }
 
-func (str String) String() string { return fmt.Sprintf("\"%s\"", string(str)) }
+// String returns the string as a double-quoted literal with the quotes, control
+// characters and invalid bytes escaped so that it can be read back.
+func (str String) String() string { return strconv.Quote(string(str)) }
+
+// Hash returns the hash of the string value.
+func (str String) Hash() (uint64, error) { return hashString(hashTagString, string(str)), nil }
+
+// Count returns the number of characters in the string.
+func (str String) Count() (int, error) { return utf8.RuneCountInString(string(str)), nil }
+
+// First returns the first character of the string. Returns nil if the string
+// is empty.
+func (str String) First() (Any, error) {
+	if str == "" {
+		return nil, nil
+	}
+	r, _ := utf8.DecodeRuneInString(string(str))
+	return Char(r), nil
+}
+
+// Next returns the string without the first character. Returns nil if there
+// are no more characters.
+func (str String) Next() (Seq, error) {
+	_, size := utf8.DecodeRuneInString(string(str))
+	if size >= len(str) {
+		return nil, nil
+	}
+	return str[size:], nil
+}
+
+// Conj returns a new string with the characters or strings appended.
+func (str String) Conj(items ...Any) (Seq, error) {
+	var b strings.Builder
+	b.WriteString(string(str))
+	for _, item := range items {
+		switch v := item.(type) {
+		case Char:
+			b.WriteRune(rune(v))
+		case String:
+			b.WriteString(string(v))
+		default:
+			return nil, Error{
+				Cause:   errors.New("invalid conj"),
+				Message: fmt.Sprintf("cannot conj value of type '%s' to string", reflect.TypeOf(item)),
+			}
+		}
+	}
+	return String(b.String()), nil
+}
 
 // Symbol represents a lisp symbol Value.
 type Symbol string
@@ -261,13 +337,58 @@ type Symbol string
 func (sym Symbol) SExpr() (string, error) { return string(sym), nil }
 
 // Equals returns true if the other Value is also a symbol and has same Value.
+// Metadata of the other symbol, if any, is ignored.
 func (sym Symbol) Equals(other Any) (bool, error) {
+	if ms, ok := other.(MetaSymbol); ok {
+		other = ms.Symbol
+	}
 	otherSym, isSym := other.(Symbol)
 	return isSym && (sym == otherSym), nil
 }
 
+// Meta returns nil since a plain symbol has no metadata. See MetaSymbol.
+func (sym Symbol) Meta() *Map { return nil }
+
+// WithMeta returns the symbol wrapped in a MetaSymbol with the given metadata.
+func (sym Symbol) WithMeta(meta *Map) Any {
+	if meta == nil {
+		return sym
+	}
+	return MetaSymbol{Symbol: sym, meta: meta}
+}
+
 func (sym Symbol) String() string { return string(sym) }
 
+// Hash returns the hash of the symbol name.
+func (sym Symbol) Hash() (uint64, error) { return hashString(hashTagSymbol, string(sym)), nil }
+
+// MetaSymbol is a Symbol with metadata (e.g., the name in (def ^:private x 1)).
+// It is created by Symbol.WithMeta and the reader's ^ macro. Special forms and
+// the analyzer treat it same as the wrapped symbol, and it is equal to and has
+// the same hash as the wrapped symbol.
+type MetaSymbol struct {
+	Symbol
+	meta *Map
+}
+
+// Meta returns the metadata of the symbol.
+func (ms MetaSymbol) Meta() *Map { return ms.meta }
+
+// WithMeta returns the symbol with the given metadata. Returns the plain
+// Symbol if meta is nil.
+func (ms MetaSymbol) WithMeta(meta *Map) Any { return ms.Symbol.WithMeta(meta) }
+
+// toSymbol returns the symbol if the form is a Symbol or a MetaSymbol.
+func toSymbol(form Any) (Symbol, bool) {
+	switch f := form.(type) {
+	case Symbol:
+		return f, true
+	case MetaSymbol:
+		return f.Symbol, true
+	}
+	return "", false
+}
+
 // Keyword represents a keyword Value.
 type Keyword string
 
@@ -282,28 +403,104 @@ func (kw Keyword) Equals(other Any) (bool, error) {
 
 func (kw Keyword) String() string { return fmt.Sprintf(":%s", string(kw)) }
 
+// Hash returns the hash of the keyword name.
+func (kw Keyword) Hash() (uint64, error) { return hashString(hashTagKeyword, string(kw)), nil }
+
 // LinkedList implements an immutable Seq using linked-list data structure.
 type LinkedList struct {
-	count int
+	count int // -1 if the rest is lazy.
 	first Any
 	rest  Seq
+	pos   *listPos
+	meta  *Map
+}
+
+// listPos holds the source span of a list or vector and of each of its items.
+type listPos struct {
+	span  Span
+	items []Span
+}
+
+// Span returns the source span of the list. Returns zero value if the list
+// was not read from source.
+func (ll *LinkedList) Span() Span {
+	if ll == nil || ll.pos == nil {
+		return Span{}
+	}
+	return ll.pos.span
+}
+
+// ItemSpan returns the source span of the i-th item in the list. Returns false
+// if the span is not known.
+func (ll *LinkedList) ItemSpan(i int) (Span, bool) {
+	if ll == nil || ll.pos == nil || i < 0 || i >= len(ll.pos.items) {
+		return Span{}, false
+	}
+	return ll.pos.items[i], true
+}
+
+// Meta returns the metadata of the list.
+func (ll *LinkedList) Meta() *Map {
+	if ll == nil {
+		return nil
+	}
+	return ll.meta
+}
+
+// WithMeta returns a copy of the list with the given metadata. The items are
+// shared with the original list.
+func (ll *LinkedList) WithMeta(meta *Map) Any {
+	res := &LinkedList{}
+	if ll != nil {
+		*res = *ll
+	}
+	res.meta = meta
+	return res
 }
 
 // SExpr returns a valid s-expression for LinkedList.
 func (ll *LinkedList) SExpr() (string, error) {
 	if ll == nil {
 		return "()", nil
+	} else if _, err := ll.Count(); err != nil {
+		return "", err
 	}
 
 	return SeqString(ll, "(", ")", " ")
 }
 
-// Equals returns true if the other value is a LinkedList and contains the same
-// values.
+// Hash returns a hash computed from the items of the list.
+func (ll *LinkedList) Hash() (uint64, error) {
+	if _, err := ll.Count(); err != nil {
+		return 0, err
+	}
+	return hashOrdered(hashTagList, ll)
+}
+
+// Equals returns true if the other value is a LinkedList, a LazySeq or a seq
+// of a vector or a map and contains the same values.
 func (ll *LinkedList) Equals(other Any) (eq bool, err error) {
+	switch o := other.(type) {
+	case *LazySeq:
+		return o.Equals(ll)
+	case *vectorSeq:
+		return seqEquals(ll, o)
+	case *mapSeq:
+		return seqEquals(ll, o)
+	}
+
 	o, ok := other.(*LinkedList)
-	if !ok || o.count != ll.count {
+	if !ok {
 		return
+	} else if ll.lazy() || o.lazy() {
+		return seqEquals(ll, o)
+	}
+
+	lc, _ := ll.Count()
+	if oc, _ := o.Count(); oc != lc {
+		return
+	} else if lc == 0 {
+		return true, nil
 	}
 
 	var s Seq = ll
@@ -363,7 +560,12 @@ func (ll *LinkedList) Next() (Seq, error) {
 func (ll *LinkedList) Count() (int, error) {
 	if ll == nil {
 		return 0, nil
+	} else if ll.count < 0 {
+		return countSeq(nil, ll)
 	}
 
 	return ll.count, nil
 }
+
+// lazy returns true if the rest of the list is lazy.
+func (ll *LinkedList) lazy() bool { return ll != nil && ll.count < 0 }
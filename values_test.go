@@ -1,6 +1,8 @@
 package parens_test
 
 import (
+	"math"
+	"math/big"
 	"testing"
 
 	"github.com/spy16/parens"
@@ -152,6 +154,93 @@ func TestComp(t *testing.T) {
 			op:   parens.Ge,
 			want: true,
 		},
+
+		// Numeric tower
+		{
+			desc: "1 == 1.",
+			a:    parens.Int64(1),
+			b:    parens.Float64(1),
+			op:   parens.Eq,
+			want: true,
+		},
+		{
+			desc: "1. == 1",
+			a:    parens.Float64(1),
+			b:    parens.Int64(1),
+			op:   parens.Eq,
+			want: true,
+		},
+		{
+			desc: "1 < 2.5",
+			a:    parens.Int64(1),
+			b:    parens.Float64(2.5),
+			op:   parens.Lt,
+			want: true,
+		},
+		{
+			desc: "2.5 > 2N",
+			a:    parens.Float64(2.5),
+			b:    parens.NewBigInt(big.NewInt(2)),
+			op:   parens.Gt,
+			want: true,
+		},
+		{
+			desc: "1/2 == 0.5",
+			a:    parens.NewRatio(big.NewRat(1, 2)),
+			b:    parens.Float64(0.5),
+			op:   parens.Eq,
+			want: true,
+		},
+		{
+			desc: "1/3 < 0.34",
+			a:    parens.NewRatio(big.NewRat(1, 3)),
+			b:    parens.Float64(0.34),
+			op:   parens.Lt,
+			want: true,
+		},
+		{
+			desc: "NaN == NaN",
+			a:    parens.Float64(math.NaN()),
+			b:    parens.Float64(math.NaN()),
+			op:   parens.Eq,
+		},
+		{
+			desc: "NaN < 1",
+			a:    parens.Float64(math.NaN()),
+			b:    parens.Int64(1),
+			op:   parens.Lt,
+		},
+		{
+			desc: "NaN <= 1",
+			a:    parens.Float64(math.NaN()),
+			b:    parens.Int64(1),
+			op:   parens.Le,
+		},
+		{
+			desc: "1 >= NaN",
+			a:    parens.Int64(1),
+			b:    parens.Float64(math.NaN()),
+			op:   parens.Ge,
+		},
+		{
+			desc: "1N > NaN",
+			a:    parens.NewBigInt(big.NewInt(1)),
+			b:    parens.Float64(math.NaN()),
+			op:   parens.Gt,
+		},
+		{
+			desc: "NaN >= NaN",
+			a:    parens.Float64(math.NaN()),
+			b:    parens.Float64(math.NaN()),
+			op:   parens.Ge,
+		},
+		{
+			desc:    "1 < :a",
+			a:       parens.Int64(1),
+			b:       parens.Keyword("a"),
+			op:      parens.Lt,
+			wantErr: true,
+		},
 		{
 			// LinkedList
 			desc: "(1 2 3) == (1 2 3)",
@@ -181,3 +270,15 @@ func TestComp(t *testing.T) {
 		})
 	}
 }
+
+func TestString_Conj(t *testing.T) {
+	t.Parallel()
+
+	got, err := parens.String("ab").Conj(parens.Char('λ'), parens.String("cd"))
+	requireNoErr(t, err)
+	assertEqual(t, parens.String("abλcd"), got)
+
+	if _, err := parens.String("ab").Conj(parens.Int64(1)); err == nil {
+		t.Errorf("expected error when conj-ing non-character value")
+	}
+}
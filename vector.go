@@ -0,0 +1,356 @@
+package parens
+
+import (
+	"fmt"
+)
+
+const (
+	vecBits  = 5
+	vecWidth = 1 << vecBits
+	vecMask  = vecWidth - 1
+)
+
+var (
+	_ Any              = (*Vector)(nil)
+	_ Seq              = (*Vector)(nil)
+	_ SExpressable     = (*Vector)(nil)
+	_ EqualityProvider = (*Vector)(nil)
+	_ Hashable         = (*Vector)(nil)
+	_ Positional       = (*Vector)(nil)
+	_ Seq              = (*vectorSeq)(nil)
+	_ EqualityProvider = (*vectorSeq)(nil)
+	_ Hashable         = (*vectorSeq)(nil)
+)
+
+// Vector is an immutable, persistent vector. Vector is implemented as a 32-way
+// trie with a tail buffer (Similar to Clojure's PersistentVector). All the
+// updates return a new vector sharing structure with the original. Nth, Assoc
+// and Conj are O(log32 n). Zero value is an empty vector ready for use.
+type Vector struct {
+	count int
+	shift uint
+	root  *vecNode
+	tail  []Any
+	meta  *Map
+	pos   *listPos
+}
+
+// vecNode is a node in the trie. Leaf nodes hold values and internal nodes
+// hold pointers to child nodes.
+type vecNode struct {
+	array [vecWidth]Any
+}
+
+// NewVector returns a new vector containing given values.
+func NewVector(items ...Any) *Vector {
+	v := &Vector{}
+	for _, item := range items {
+		v = v.conj(item)
+	}
+	return v
+}
+
+// NewPositionalVector returns a new vector containing given values and
+// annotated with the source span of the vector and of each of the items. Item
+// spans are retrieved using ItemSpan().
+func NewPositionalVector(span Span, items []Any, itemSpans []Span) *Vector {
+	v := NewVector(items...)
+	v.pos = &listPos{span: span, items: itemSpans}
+	return v
+}
+
+// Span returns the source span of the vector. Vectors that are not read from
+// source or are derived from another vector have no span.
+func (v *Vector) Span() Span {
+	if v == nil || v.pos == nil {
+		return Span{}
+	}
+	return v.pos.span
+}
+
+// ItemSpan returns the source span of the i-th item in the vector. Returns
+// false if the span is not known.
+func (v *Vector) ItemSpan(i int) (Span, bool) {
+	if v == nil || v.pos == nil || i < 0 || i >= len(v.pos.items) {
+		return Span{}, false
+	}
+	return v.pos.items[i], true
+}
+
+// SExpr returns a valid s-expression for Vector.
+func (v *Vector) SExpr() (string, error) {
+	if v.Size() == 0 {
+		return "[]", nil
+	}
+	return SeqString(v, "[", "]", " ")
+}
+
+// Equals returns true if the other value is also a Vector and contains the
+// same values in the same order.
+func (v *Vector) Equals(other Any) (bool, error) {
+	o, ok := other.(*Vector)
+	if !ok || o.Size() != v.Size() {
+		return false, nil
+	}
+
+	for i := 0; i < v.Size(); i++ {
+		eq, err := Eq(v.nth(i), o.nth(i))
+		if err != nil || !eq {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Hash returns a hash computed from the items of the vector.
+func (v *Vector) Hash() (uint64, error) { return hashOrdered(hashTagVector, v) }
+
+// Size returns the number of items in the vector.
+func (v *Vector) Size() int {
+	if v == nil {
+		return 0
+	}
+	return v.count
+}
+
+// Count returns the number of items in the vector.
+func (v *Vector) Count() (int, error) { return v.Size(), nil }
+
+// First returns the first item of the vector. Returns nil if the vector is
+// empty.
+func (v *Vector) First() (Any, error) {
+	if v.Size() == 0 {
+		return nil, nil
+	}
+	return v.nth(0), nil
+}
+
+// Next returns a sequence of all the items in the vector except the first.
+func (v *Vector) Next() (Seq, error) {
+	if v.Size() == 0 {
+		return nil, nil
+	}
+	return &vectorSeq{vec: v, offset: 1}, nil
+}
+
+// Conj returns a new vector with the items added at the end.
+func (v *Vector) Conj(items ...Any) (Seq, error) {
+	res := v
+	if res == nil {
+		res = &Vector{}
+	}
+
+	for _, item := range items {
+		res = res.conj(item)
+	}
+	return res, nil
+}
+
+// Meta returns the metadata of the vector.
+func (v *Vector) Meta() *Map {
+	if v == nil {
+		return nil
+	}
+	return v.meta
+}
+
+// WithMeta returns a copy of the vector with the given metadata.
+func (v *Vector) WithMeta(meta *Map) Any {
+	res := &Vector{}
+	if v != nil {
+		*res = *v
+	}
+	res.meta = meta
+	return res
+}
+
+// Nth returns the item at index i. Returns error with ErrIndexOutOfBounds
+// cause if the index is not within the vector.
+func (v *Vector) Nth(i int) (Any, error) {
+	if i < 0 || i >= v.Size() {
+		return nil, v.boundsErr(i)
+	}
+	return v.nth(i), nil
+}
+
+// Assoc returns a new vector with the item at index i replaced with val. If
+// i is same as the count of the vector, val is added at the end. Returns error
+// with ErrIndexOutOfBounds cause if the index is not within the vector.
+func (v *Vector) Assoc(i int, val Any) (*Vector, error) {
+	if i == v.Size() {
+		if v == nil {
+			v = &Vector{}
+		}
+		return v.conj(val), nil
+	} else if i < 0 || i > v.Size() {
+		return nil, v.boundsErr(i)
+	}
+
+	if i >= v.tailOffset() {
+		tail := make([]Any, len(v.tail))
+		copy(tail, v.tail)
+		tail[i&vecMask] = val
+		return &Vector{count: v.count, shift: v.shift, root: v.root, tail: tail, meta: v.meta}, nil
+	}
+
+	return &Vector{
+		count: v.count,
+		shift: v.shift,
+		root:  assocNode(v.shift, v.root, i, val),
+		tail:  v.tail,
+		meta:  v.meta,
+	}, nil
+}
+
+func (v *Vector) boundsErr(i int) error {
+	return Error{
+		Cause:   ErrIndexOutOfBounds,
+		Message: fmt.Sprintf("index %d, count %d", i, v.Size()),
+	}
+}
+
+// tailOffset returns the index of the first item in the tail.
+func (v *Vector) tailOffset() int {
+	if v.count < vecWidth {
+		return 0
+	}
+	return ((v.count - 1) >> vecBits) << vecBits
+}
+
+func (v *Vector) nth(i int) Any {
+	if i >= v.tailOffset() {
+		return v.tail[i&vecMask]
+	}
+
+	node := v.root
+	for level := v.shift; level > 0; level -= vecBits {
+		node = node.array[(i>>level)&vecMask].(*vecNode)
+	}
+	return node.array[i&vecMask]
+}
+
+func (v *Vector) conj(val Any) *Vector {
+	root, shift := v.root, v.shift
+	if root == nil {
+		root, shift = &vecNode{}, vecBits
+	}
+
+	if v.count-v.tailOffset() < vecWidth {
+		// room in the tail.
+		tail := make([]Any, len(v.tail)+1)
+		copy(tail, v.tail)
+		tail[len(v.tail)] = val
+		return &Vector{count: v.count + 1, shift: shift, root: root, tail: tail, meta: v.meta}
+	}
+
+	// tail is full, push it into the trie.
+	tailNode := &vecNode{}
+	copy(tailNode.array[:], v.tail)
+
+	if (v.count >> vecBits) > (1 << shift) {
+		// root is full, add a new level.
+		newRoot := &vecNode{}
+		newRoot.array[0] = root
+		newRoot.array[1] = newPath(shift, tailNode)
+		root, shift = newRoot, shift+vecBits
+	} else {
+		root = pushTail(v.count, shift, root, tailNode)
+	}
+
+	return &Vector{count: v.count + 1, shift: shift, root: root, tail: []Any{val}, meta: v.meta}
+}
+
+func pushTail(count int, level uint, parent, tailNode *vecNode) *vecNode {
+	subIdx := ((count - 1) >> level) & vecMask
+	res := &vecNode{array: parent.array}
+
+	var child *vecNode
+	if level == vecBits {
+		child = tailNode
+	} else if existing, ok := parent.array[subIdx].(*vecNode); ok {
+		child = pushTail(count, level-vecBits, existing, tailNode)
+	} else {
+		child = newPath(level-vecBits, tailNode)
+	}
+
+	res.array[subIdx] = child
+	return res
+}
+
+func newPath(level uint, node *vecNode) *vecNode {
+	if level == 0 {
+		return node
+	}
+
+	res := &vecNode{}
+	res.array[0] = newPath(level-vecBits, node)
+	return res
+}
+
+func assocNode(level uint, node *vecNode, i int, val Any) *vecNode {
+	res := &vecNode{array: node.array}
+	if level == 0 {
+		res.array[i&vecMask] = val
+		return res
+	}
+
+	subIdx := (i >> level) & vecMask
+	res.array[subIdx] = assocNode(level-vecBits, node.array[subIdx].(*vecNode), i, val)
+	return res
+}
+
+// vectorSeq is a Seq view of a vector starting at offset.
+type vectorSeq struct {
+	vec    *Vector
+	offset int
+}
+
+func (vs *vectorSeq) Count() (int, error) { return vs.vec.Size() - vs.offset, nil }
+
+func (vs *vectorSeq) First() (Any, error) {
+	if vs.offset >= vs.vec.Size() {
+		return nil, nil
+	}
+	return vs.vec.nth(vs.offset), nil
+}
+
+func (vs *vectorSeq) Next() (Seq, error) {
+	if vs.offset >= vs.vec.Size() {
+		return nil, nil
+	}
+	return &vectorSeq{vec: vs.vec, offset: vs.offset + 1}, nil
+}
+
+func (vs *vectorSeq) Conj(items ...Any) (res Seq, err error) {
+	res = vs
+	for _, item := range items {
+		if res, err = Cons(item, res); err != nil {
+			break
+		}
+	}
+	return
+}
+
+func (vs *vectorSeq) SExpr() (string, error) {
+	return SeqString(vs, "(", ")", " ")
+}
+
+// Equals returns true if the other value is a list or a seq of a vector or a
+// map and contains the same values in the same order, same as LinkedList.
+func (vs *vectorSeq) Equals(other Any) (bool, error) {
+	switch o := other.(type) {
+	case *vectorSeq:
+		return seqEquals(vs, o)
+	case *mapSeq:
+		return seqEquals(vs, o)
+	case *LinkedList:
+		return seqEquals(vs, o)
+	case *LazySeq:
+		return o.Equals(vs)
+	}
+	return false, nil
+}
+
+// Hash returns the same hash as a list with the same items.
+func (vs *vectorSeq) Hash() (uint64, error) { return hashOrdered(hashTagList, vs) }
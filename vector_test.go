@@ -0,0 +1,242 @@
+package parens_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spy16/parens"
+)
+
+func TestVector_Conj(t *testing.T) {
+	t.Parallel()
+
+	// large enough to need 3 levels of internal nodes in the trie.
+	const n = 32*32*32 + 100
+
+	var vec parens.Seq = parens.NewVector()
+	for i := 0; i < n; i++ {
+		var err error
+		vec, err = vec.Conj(parens.Int64(i))
+		requireNoErr(t, err)
+	}
+
+	cnt, err := vec.Count()
+	requireNoErr(t, err)
+	assertEqual(t, n, cnt)
+
+	v := vec.(*parens.Vector)
+	for i := 0; i < n; i++ {
+		item, err := v.Nth(i)
+		requireNoErr(t, err)
+		if item != parens.Int64(i) {
+			t.Fatalf("Nth(%d) = %v, want %d", i, item, i)
+		}
+	}
+}
+
+func TestVector_Assoc(t *testing.T) {
+	t.Parallel()
+
+	items := make([]parens.Any, 1100)
+	for i := range items {
+		items[i] = parens.Int64(i)
+	}
+	orig := parens.NewVector(items...)
+
+	for _, i := range []int{0, 31, 32, 1023, 1024, 1099} {
+		updated, err := orig.Assoc(i, parens.Keyword("x"))
+		requireNoErr(t, err)
+
+		got, err := updated.Nth(i)
+		requireNoErr(t, err)
+		assertEqual(t, parens.Keyword("x"), got)
+
+		// original must not be modified.
+		got, err = orig.Nth(i)
+		requireNoErr(t, err)
+		assertEqual(t, parens.Int64(i), got)
+	}
+
+	appended, err := orig.Assoc(1100, parens.Keyword("end"))
+	requireNoErr(t, err)
+	assertEqual(t, 1101, appended.Size())
+	assertEqual(t, 1100, orig.Size())
+
+	_, err = orig.Assoc(1101, parens.Nil{})
+	if !errors.Is(err, parens.ErrIndexOutOfBounds) {
+		t.Errorf("expected ErrIndexOutOfBounds, got %#v", err)
+	}
+}
+
+func TestVector_Nth_OutOfBounds(t *testing.T) {
+	t.Parallel()
+
+	vec := parens.NewVector(parens.Int64(1))
+	for _, i := range []int{-1, 1} {
+		if _, err := vec.Nth(i); !errors.Is(err, parens.ErrIndexOutOfBounds) {
+			t.Errorf("Nth(%d): expected ErrIndexOutOfBounds, got %#v", i, err)
+		}
+	}
+}
+
+func TestVector_Seq(t *testing.T) {
+	t.Parallel()
+
+	vec := parens.NewVector(parens.Int64(1), parens.Int64(2), parens.Int64(3))
+
+	first, err := vec.First()
+	requireNoErr(t, err)
+	assertEqual(t, parens.Int64(1), first)
+
+	next, err := vec.Next()
+	requireNoErr(t, err)
+
+	cnt, err := next.Count()
+	requireNoErr(t, err)
+	assertEqual(t, 2, cnt)
+
+	s, err := parens.SeqString(next, "(", ")", " ")
+	requireNoErr(t, err)
+	assertEqual(t, "(2 3)", s)
+
+	empty := parens.NewVector()
+	first, err = empty.First()
+	requireNoErr(t, err)
+	assertEqual(t, nil, first)
+}
+
+func TestVector_SExpr(t *testing.T) {
+	t.Parallel()
+
+	s, err := parens.NewVector().SExpr()
+	requireNoErr(t, err)
+	assertEqual(t, "[]", s)
+
+	s, err = parens.NewVector(parens.Int64(1), parens.Keyword("a"), parens.NewVector()).SExpr()
+	requireNoErr(t, err)
+	assertEqual(t, "[1 :a []]", s)
+}
+
+func TestVector_Equals(t *testing.T) {
+	t.Parallel()
+
+	a := parens.NewVector(parens.Int64(1), parens.NewVector(parens.Keyword("a")))
+
+	for _, tt := range []struct {
+		desc  string
+		other parens.Any
+		want  bool
+	}{
+		{
+			desc:  "same items",
+			other: parens.NewVector(parens.Int64(1), parens.NewVector(parens.Keyword("a"))),
+			want:  true,
+		},
+		{
+			desc:  "different items",
+			other: parens.NewVector(parens.Int64(1), parens.NewVector(parens.Keyword("b"))),
+		},
+		{
+			desc:  "different count",
+			other: parens.NewVector(parens.Int64(1)),
+		},
+		{
+			desc:  "list with same items",
+			other: parens.NewList(parens.Int64(1), parens.NewVector(parens.Keyword("a"))),
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := parens.Eq(a, tt.other)
+			requireNoErr(t, err)
+			assertEqual(t, tt.want, got)
+		})
+	}
+}
+
+func TestVector_Seq_Equals(t *testing.T) {
+	t.Parallel()
+
+	rest := func(items ...parens.Any) parens.Seq {
+		next, err := parens.NewVector(items...).Next()
+		requireNoErr(t, err)
+		return next
+	}
+
+	one, two, three := parens.Int64(1), parens.Int64(2), parens.Int64(3)
+	seq := rest(one, two, three)
+
+	for _, tt := range []struct {
+		desc  string
+		other parens.Any
+		want  bool
+	}{
+		{desc: "same seq", other: rest(one, two, three), want: true},
+		{desc: "seq of different vector", other: rest(three, two, three), want: true},
+		{desc: "list with same items", other: parens.NewList(two, three), want: true},
+		{desc: "list with different items", other: parens.NewList(two, one)},
+		{desc: "shorter seq", other: rest(one, two)},
+		{desc: "vector with same items", other: parens.NewVector(two, three)},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := parens.Eq(seq, tt.other)
+			requireNoErr(t, err)
+			assertEqual(t, tt.want, got)
+
+			// equality must be symmetric.
+			got, err = parens.Eq(tt.other, seq)
+			requireNoErr(t, err)
+			assertEqual(t, tt.want, got)
+		})
+	}
+
+	// seqs of vectors can be used as map keys.
+	m, err := parens.NewMap(rest(one, two), one)
+	requireNoErr(t, err)
+
+	v, found, err := m.Get(parens.NewList(two))
+	requireNoErr(t, err)
+	assertEqual(t, true, found)
+	assertEqual(t, one, v)
+}
+
+func TestVector_Eval(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		title string
+		src   string
+		want  parens.Any
+	}{
+		{
+			title: "Items Evaluated",
+			src:   `(def x :a) [x (quote y) [x]]`,
+			want:  parens.NewVector(parens.Keyword("a"), parens.Symbol("y"), parens.NewVector(parens.Keyword("a"))),
+		},
+		{
+			title: "Vector Params",
+			src:   `((fn [a b] b) 1 2)`,
+			want:  parens.Int64(2),
+		},
+		{
+			title: "Vector Let Bindings",
+			src:   `(let [a 1 b a] [a b])`,
+			want:  parens.NewVector(parens.Int64(1), parens.Int64(1)),
+		},
+		{
+			title: "Syntax Quote",
+			src:   "(def x 1) `[a ~x ~@(quote (2 3))]",
+			want:  parens.NewVector(parens.Symbol("user/a"), parens.Int64(1), parens.Int64(2), parens.Int64(3)),
+		},
+	} {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := evalString(parens.New(), tt.src)
+			requireNoErr(t, err)
+
+			eq, err := parens.Eq(tt.want, got)
+			requireNoErr(t, err)
+			if !eq {
+				t.Errorf("Eval() got = %v, want = %v", got, tt.want)
+			}
+		})
+	}
+}